@@ -0,0 +1,73 @@
+package meshful
+
+// An AABB is an axis-aligned bounding box described by its minimum and maximum corners
+type AABB struct {
+	Min Vec3
+	Max Vec3
+}
+
+// Center returns the point in the middle of the box
+func (box AABB) Center() Vec3 {
+	return box.Min.Add(box.Max).Scale(0.5)
+}
+
+// Size returns the extents of the box along each axis
+func (box AABB) Size() Vec3 {
+	return box.Max.Diff(box.Min)
+}
+
+// Union returns the smallest box that contains both boxes
+func (box AABB) Union(other AABB) AABB {
+	return AABB{
+		Min: box.Min.Min(other.Min),
+		Max: box.Max.Max(other.Max),
+	}
+}
+
+// Intersection returns the overlapping region of two boxes. The second return value is false
+// if the boxes do not overlap
+func (box AABB) Intersection(other AABB) (AABB, bool) {
+	overlap := AABB{
+		Min: box.Min.Max(other.Min),
+		Max: box.Max.Min(other.Max),
+	}
+	if overlap.Min.X > overlap.Max.X || overlap.Min.Y > overlap.Max.Y || overlap.Min.Z > overlap.Max.Z {
+		return AABB{}, false
+	}
+	return overlap, true
+}
+
+// Intersects reports whether two boxes overlap. Boxes that only touch are considered overlapping
+func (box AABB) Intersects(other AABB) bool {
+	_, ok := box.Intersection(other)
+	return ok
+}
+
+// Contains reports whether a point lies inside or on the boundary of the box
+func (box AABB) Contains(point Vec3) bool {
+	return point.X >= box.Min.X && point.X <= box.Max.X &&
+		point.Y >= box.Min.Y && point.Y <= box.Max.Y &&
+		point.Z >= box.Min.Z && point.Z <= box.Max.Z
+}
+
+// ContainsBox reports whether the other box lies completely inside this box
+func (box AABB) ContainsBox(other AABB) bool {
+	return box.Contains(other.Min) && box.Contains(other.Max)
+}
+
+// Expand returns the smallest box that contains both the box and the point
+func (box AABB) Expand(point Vec3) AABB {
+	return AABB{
+		Min: box.Min.Min(point),
+		Max: box.Max.Max(point),
+	}
+}
+
+// Pad returns the box grown by margin in every direction. A negative margin shrinks the box
+func (box AABB) Pad(margin float32) AABB {
+	m := Vec3{margin, margin, margin}
+	return AABB{
+		Min: box.Min.Diff(m),
+		Max: box.Max.Add(m),
+	}
+}
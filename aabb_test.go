@@ -0,0 +1,55 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestAABBUnionAndIntersection(t *testing.T) {
+	a := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{2, 2, 2}}
+	b := AABB{Min: Vec3{1, 1, 1}, Max: Vec3{3, 3, 3}}
+
+	union := a.Union(b)
+	if union.Min != (Vec3{0, 0, 0}) || union.Max != (Vec3{3, 3, 3}) {
+		t.Errorf("Expected union from {0 0 0} to {3 3 3}, found: %v", union)
+	}
+
+	overlap, ok := a.Intersection(b)
+	if !ok {
+		t.Fatal("Expected boxes to intersect")
+	}
+	if overlap.Min != (Vec3{1, 1, 1}) || overlap.Max != (Vec3{2, 2, 2}) {
+		t.Errorf("Expected intersection from {1 1 1} to {2 2 2}, found: %v", overlap)
+	}
+
+	c := AABB{Min: Vec3{5, 5, 5}, Max: Vec3{6, 6, 6}}
+	if a.Intersects(c) {
+		t.Errorf("Expected disjoint boxes to not intersect")
+	}
+}
+
+func TestAABBContainment(t *testing.T) {
+	box := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{2, 2, 2}}
+
+	if box.Center() != (Vec3{1, 1, 1}) {
+		t.Errorf("Expected center of {1 1 1}, found: %v", box.Center())
+	}
+	if !box.Contains(Vec3{2, 0, 1}) {
+		t.Errorf("Expected point on the boundary to be contained")
+	}
+	if box.Contains(Vec3{3, 0, 1}) {
+		t.Errorf("Expected outside point to not be contained")
+	}
+	if !box.ContainsBox(AABB{Min: Vec3{0.5, 0.5, 0.5}, Max: Vec3{1, 1, 1}}) {
+		t.Errorf("Expected inner box to be contained")
+	}
+
+	expanded := box.Expand(Vec3{-1, 3, 1})
+	if expanded.Min != (Vec3{-1, 0, 0}) || expanded.Max != (Vec3{2, 3, 2}) {
+		t.Errorf("Expected expanded box from {-1 0 0} to {2 3 2}, found: %v", expanded)
+	}
+
+	padded := box.Pad(1)
+	if padded.Size() != (Vec3{4, 4, 4}) {
+		t.Errorf("Expected padded size of {4 4 4}, found: %v", padded.Size())
+	}
+}
@@ -0,0 +1,182 @@
+package meshful
+
+import (
+	"math"
+)
+
+// HealthReport describes problems with a mesh that would make it unsuitable for printing or
+// make its measurements unreliable
+type HealthReport struct {
+	Triangles int `json:"triangles"`
+	Vertices  int `json:"vertices"`
+
+	// a watertight mesh has no boundary or non-manifold edges
+	Watertight bool `json:"watertight"`
+
+	// edges used by only one triangle
+	BoundaryEdges int `json:"boundaryEdges"`
+
+	// edges used by more than two triangles
+	NonManifoldEdges int `json:"nonManifoldEdges"`
+
+	// vertices where the surrounding triangles do not form a single connected fan
+	NonManifoldVertices int `json:"nonManifoldVertices"`
+
+	// triangles with zero or almost zero area
+	DegenerateTriangles int `json:"degenerateTriangles"`
+
+	// extra copies of triangles that use the same 3 vertices as another triangle
+	DuplicateTriangles int `json:"duplicateTriangles"`
+
+	// triangles whose winding disagrees with the rest of their shell or whose shell is inside out
+	FlippedTriangles int `json:"flippedTriangles"`
+
+	// triangles whose stored normal disagrees with their winding
+	MismatchedNormals int `json:"mismatchedNormals"`
+
+	// number of edge connected pieces of the mesh
+	Shells int `json:"shells"`
+
+	// pairs of triangles that cross each other
+	SelfIntersections int `json:"selfIntersections"`
+
+	// whether Volume() can be trusted, which requires a watertight and consistently outward
+	// oriented mesh without self-intersections and with a positive volume
+	VolumeReliable bool `json:"volumeReliable"`
+}
+
+// maximum angle in degrees between a stored normal and the winding before it counts as mismatched
+const mismatchedNormalAngle = 45
+
+// Analyze inspects the topology and geometry of the mesh and returns a report of its problems
+func (mesh *Mesh) Analyze() HealthReport {
+	im := newIndexedMesh(mesh)
+	edges := im.edgeFaces()
+
+	report := HealthReport{
+		Triangles: len(im.faces),
+		Vertices:  len(im.vertices),
+	}
+
+	for _, faces := range edges {
+		if len(faces) == 1 {
+			report.BoundaryEdges++
+		} else if len(faces) > 2 {
+			report.NonManifoldEdges++
+		}
+	}
+	report.Watertight = len(im.faces) > 0 && report.BoundaryEdges == 0 && report.NonManifoldEdges == 0
+	report.NonManifoldVertices = len(im.nonManifoldVertices())
+
+	for i := range mesh.Triangles {
+		if mesh.Triangles[i].isDegenerate() {
+			report.DegenerateTriangles++
+		}
+	}
+	report.DuplicateTriangles = len(im.duplicateFaces())
+
+	for _, f := range im.orientationFlips(edges) {
+		if f {
+			report.FlippedTriangles++
+		}
+	}
+	report.MismatchedNormals = len(mesh.InconsistentNormals(mismatchedNormalAngle))
+	report.Shells = len(im.components(edges))
+	report.SelfIntersections = len(mesh.SelfIntersections())
+
+	report.VolumeReliable = report.Watertight && report.FlippedTriangles == 0 &&
+		report.SelfIntersections == 0 && mesh.Volume() > 0
+	return report
+}
+
+// isDegenerate reports whether the triangle has zero area or is so thin that its area is
+// negligible compared to its longest edge
+func (t *Triangle) isDegenerate() bool {
+	a := toVec3d(t.Vertices[0])
+	b := toVec3d(t.Vertices[1])
+	c := toVec3d(t.Vertices[2])
+	longest := math.Max(b.sub(a).length(), math.Max(c.sub(b).length(), a.sub(c).length()))
+	if longest == 0 {
+		return true
+	}
+	// twice the area divided by the longest edge is the height of the triangle
+	height := b.sub(a).cross(c.sub(a)).length() / longest
+	return height <= longest*1e-6
+}
+
+// duplicateFaces returns the faces that use the same set of vertices as an earlier face,
+// regardless of their winding
+func (im *indexedMesh) duplicateFaces() []int {
+	seen := make(map[[3]int]bool)
+	duplicates := []int{}
+	for i, face := range im.faces {
+		key := sortedFace(face)
+		if seen[key] {
+			duplicates = append(duplicates, i)
+		}
+		seen[key] = true
+	}
+	return duplicates
+}
+
+func sortedFace(face [3]int) [3]int {
+	if face[0] > face[1] {
+		face[0], face[1] = face[1], face[0]
+	}
+	if face[1] > face[2] {
+		face[1], face[2] = face[2], face[1]
+	}
+	if face[0] > face[1] {
+		face[0], face[1] = face[1], face[0]
+	}
+	return face
+}
+
+// nonManifoldVertices returns the vertices whose faces split into more than one group when only
+// faces sharing an edge through the vertex are connected
+func (im *indexedMesh) nonManifoldVertices() []int {
+	vertices := []int{}
+	for v, faces := range im.vertexFaces() {
+		if len(faces) < 2 {
+			continue
+		}
+
+		// union the faces that share an edge leaving this vertex
+		parent := make(map[int]int)
+		var find func(int) int
+		find = func(f int) int {
+			for parent[f] != f {
+				parent[f] = parent[parent[f]]
+				f = parent[f]
+			}
+			return f
+		}
+		byOther := make(map[int]int)
+		for _, f := range faces {
+			parent[f] = f
+		}
+		for _, f := range faces {
+			for _, other := range im.faces[f] {
+				if other == v {
+					continue
+				}
+				if g, exists := byOther[other]; exists {
+					parent[find(f)] = find(g)
+				} else {
+					byOther[other] = f
+				}
+			}
+		}
+
+		groups := 0
+		for _, f := range faces {
+			if find(f) == f {
+				groups++
+			}
+		}
+		if groups > 1 {
+			vertices = append(vertices, v)
+		}
+	}
+	return vertices
+}
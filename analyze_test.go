@@ -0,0 +1,85 @@
+package meshful
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeHealthyMesh(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	report := mesh.Analyze()
+
+	expected := HealthReport{
+		Triangles:      12,
+		Vertices:       8,
+		Watertight:     true,
+		Shells:         1,
+		VolumeReliable: true,
+	}
+	if report != expected {
+		t.Errorf("Expected report %+v, found: %+v", expected, report)
+	}
+}
+
+func TestAnalyzeOpenMesh(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	// remove a triangle to open a hole
+	mesh.Triangles = mesh.Triangles[1:]
+	// another box that only touches the first at a corner
+	mesh.Triangles = append(mesh.Triangles, makeBoxMesh(Vec3{1, 1, 1}, Vec3{2, 2, 2}).Triangles...)
+	// flip a triangle of the second box
+	mesh.Triangles[len(mesh.Triangles)-1].Flip()
+
+	report := mesh.Analyze()
+	if report.Watertight || report.VolumeReliable {
+		t.Errorf("Expected an open mesh to not be watertight")
+	}
+	if report.BoundaryEdges != 3 {
+		t.Errorf("Expected 3 boundary edges, found: %d", report.BoundaryEdges)
+	}
+	if report.NonManifoldVertices != 1 {
+		t.Errorf("Expected 1 non-manifold vertex, found: %d", report.NonManifoldVertices)
+	}
+	if report.FlippedTriangles != 1 {
+		t.Errorf("Expected 1 flipped triangle, found: %d", report.FlippedTriangles)
+	}
+	if report.Shells != 2 {
+		t.Errorf("Expected 2 shells, found: %d", report.Shells)
+	}
+	if report.SelfIntersections != 0 {
+		t.Errorf("Expected boxes touching at a corner to not intersect, found: %d", report.SelfIntersections)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"boundaryEdges":3`) {
+		t.Errorf("Expected boundaryEdges in the JSON report, found: %s", data)
+	}
+}
+
+func TestAnalyzeDuplicateAndDegenerateTriangles(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	duplicate := mesh.Triangles[4]
+	duplicate.Flip()
+	mesh.Triangles = append(mesh.Triangles, duplicate, Triangle{
+		Vertices: [3]Vec3{{0, 0, 0}, {0.5, 0.5, 0.5}, {1, 1, 1}},
+	})
+
+	report := mesh.Analyze()
+	if report.DuplicateTriangles != 1 {
+		t.Errorf("Expected 1 duplicate triangle, found: %d", report.DuplicateTriangles)
+	}
+	if report.DegenerateTriangles != 1 {
+		t.Errorf("Expected 1 degenerate triangle, found: %d", report.DegenerateTriangles)
+	}
+	// the duplicate shares all of its edges with two other triangles
+	if report.NonManifoldEdges != 3 {
+		t.Errorf("Expected 3 non-manifold edges, found: %d", report.NonManifoldEdges)
+	}
+	if report.Watertight {
+		t.Errorf("Expected a mesh with non-manifold edges to not be watertight")
+	}
+}
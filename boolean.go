@@ -0,0 +1,516 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrOpenMesh is used when an operation needs a closed mesh and an edge is not shared by exactly
+// two triangles
+var ErrOpenMesh = errors.New("Mesh is not closed")
+
+type booleanOp int
+
+const (
+	unionOp booleanOp = iota
+	differenceOp
+	intersectionOp
+)
+
+// where a piece of one mesh's surface lies relative to the other mesh
+const (
+	outsideSurface = iota
+	insideSurface
+	// on a face of the other mesh that points the same way
+	sameSurface
+	// on a face of the other mesh that points the opposite way
+	oppositeSurface
+)
+
+// Union returns a closed mesh of the space inside either mesh
+func (mesh *Mesh) Union(other *Mesh) (*Mesh, error) {
+	return boolean(mesh, other, unionOp)
+}
+
+// Difference returns a closed mesh of the space inside this mesh but outside the other one
+func (mesh *Mesh) Difference(other *Mesh) (*Mesh, error) {
+	return boolean(mesh, other, differenceOp)
+}
+
+// Intersection returns a closed mesh of the space inside both meshes
+func (mesh *Mesh) Intersection(other *Mesh) (*Mesh, error) {
+	return boolean(mesh, other, intersectionOp)
+}
+
+// boolean combines two closed meshes. Triangle pairs found with a BVH are intersected using exact
+// orientation predicates, and every triangle that is crossed is split along the intersection
+// segments. Crossing points are computed from the edge and plane that create them so the triangles
+// on both sides of an edge are split at identical points and the result stays closed. Pieces are
+// then kept or dropped based on their winding number in the other mesh
+func boolean(a, b *Mesh, op booleanOp) (*Mesh, error) {
+	for _, m := range []*Mesh{a, b} {
+		if len(m.Triangles) == 0 {
+			return nil, ErrEmptyMesh
+		}
+		if !m.isClosed() {
+			return nil, ErrOpenMesh
+		}
+	}
+	bvhA, bvhB := NewBVH(a), NewBVH(b)
+
+	segmentsA := make(map[int][][2]vec3d)
+	segmentsB := make(map[int][][2]vec3d)
+	touchedA := make(map[int]bool)
+	touchedB := make(map[int]bool)
+	for i := range a.Triangles {
+		ta := triangle64(&a.Triangles[i])
+		for _, j := range bvhB.QueryBox(a.Triangles[i].boundingBox()) {
+			tb := triangle64(&b.Triangles[j])
+			kind, segment := triangleIntersection(ta, tb)
+			switch kind {
+			case segmentIntersection:
+				// triangles that only touch at a point do not need to be split
+				if segment[0] != segment[1] {
+					segmentsA[i] = append(segmentsA[i], segment)
+					segmentsB[j] = append(segmentsB[j], segment)
+				}
+			case coplanarTriangles:
+				if coplanarOverlap(ta, tb) {
+					touchedA[i] = true
+					touchedB[j] = true
+				}
+			}
+		}
+	}
+
+	// offset used to tell which side of a face the other mesh is on
+	boxA, _ := a.BoundingBox()
+	boxB, _ := b.BoundingBox()
+	size := toVec3d(boxA.Union(boxB).Size()).length()
+	eps := 1e-6 * size
+
+	snapPoints([]*BVH{bvhA, bvhB}, 1e-8*size, segmentsA, segmentsB)
+	addEdgePoints(a, bvhA, segmentsA)
+	addEdgePoints(b, bvhB, segmentsB)
+
+	result := &Mesh{}
+	a.collectPieces(bvhB, segmentsA, touchedA, eps, func(class int) bool {
+		switch op {
+		case unionOp:
+			return class == outsideSurface || class == sameSurface
+		case differenceOp:
+			return class == outsideSurface || class == oppositeSurface
+		default:
+			return class == insideSurface || class == sameSurface
+		}
+	}, false, result)
+	// faces of b that lie on faces of a are never kept since a already provides them when needed
+	b.collectPieces(bvhA, segmentsB, touchedB, eps, func(class int) bool {
+		if op == unionOp {
+			return class == outsideSurface
+		}
+		return class == insideSurface
+	}, op == differenceOp, result)
+	return result, nil
+}
+
+// isClosed reports whether every edge of the mesh is shared by exactly two triangles
+func (mesh *Mesh) isClosed() bool {
+	for _, faces := range newIndexedMesh(mesh).edgeFaces() {
+		if len(faces) != 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// snapPoints moves intersection points that are within tolerance of a mesh vertex onto the vertex.
+// Such points come from surfaces that almost touch at a vertex, and snapping them everywhere at
+// once keeps both meshes split at the same points
+func snapPoints(bvhs []*BVH, tolerance float64, segments ...map[int][][2]vec3d) {
+	snapped := make(map[vec3d]vec3d)
+	snap := func(p vec3d) vec3d {
+		if q, ok := snapped[p]; ok {
+			return q
+		}
+		q, best := p, tolerance
+		point := p.vec3()
+		for _, bvh := range bvhs {
+			for _, i := range bvh.QueryBox(AABB{Min: point, Max: point}.Pad(float32(tolerance) + pointPad(p))) {
+				for _, v := range bvh.mesh.Triangles[i].Vertices {
+					if d := toVec3d(v).sub(p).length(); d <= best {
+						q, best = toVec3d(v), d
+					}
+				}
+			}
+		}
+		snapped[p] = q
+		return q
+	}
+	for _, list := range segments {
+		for _, l := range list {
+			for i := range l {
+				l[i] = [2]vec3d{snap(l[i][0]), snap(l[i][1])}
+			}
+		}
+	}
+}
+
+// pointPad is more than the rounding error of converting the point to single precision
+func pointPad(p vec3d) float32 {
+	return float32(1e-6 * (1 + math.Max(math.Abs(p.x), math.Max(math.Abs(p.y), math.Abs(p.z)))))
+}
+
+// addEdgePoints makes sure every triangle is split at the intersection points that lie on its
+// edges. When the other mesh crosses exactly through an edge only one of the two triangles that
+// share it may have an intersection segment there, and without the point the other triangle would
+// leave a gap. The points are added as zero length segments
+func addEdgePoints(mesh *Mesh, bvh *BVH, segments map[int][][2]vec3d) {
+	points := make(map[vec3d]bool)
+	for _, list := range segments {
+		for _, s := range list {
+			points[s[0]] = true
+			points[s[1]] = true
+		}
+	}
+	for p := range points {
+		point := p.vec3()
+		for _, i := range bvh.QueryBox(AABB{Min: point, Max: point}.Pad(pointPad(p))) {
+			t := triangle64(&mesh.Triangles[i])
+			for k := 0; k < 3; k++ {
+				if onSegment(p, t[k], t[(k+1)%3]) {
+					segments[i] = append(segments[i], [2]vec3d{p, p})
+					break
+				}
+			}
+		}
+	}
+}
+
+// onSegment reports whether p lies on the segment between a and b, not counting its ends
+func onSegment(p, a, b vec3d) bool {
+	if p == a || p == b {
+		return false
+	}
+	ab := b.sub(a)
+	length := ab.length()
+	t := p.sub(a).dot(ab) / (length * length)
+	return t > 0 && t < 1 && ab.cross(p.sub(a)).length()/length <= 1e-9*length
+}
+
+func triangle64(t *Triangle) [3]vec3d {
+	return [3]vec3d{toVec3d(t.Vertices[0]), toVec3d(t.Vertices[1]), toVec3d(t.Vertices[2])}
+}
+
+// collectPieces adds the parts of the mesh's surface that keep accepts to the result, reversing
+// them if flip is set. Triangles that were not crossed are grouped into patches joined by their
+// edges, and since a patch cannot cross the other mesh without one of its triangles being crossed
+// a single classification covers the whole patch. The pieces of crossed triangles are classified
+// one by one
+func (mesh *Mesh) collectPieces(other *BVH, segments map[int][][2]vec3d, touched map[int]bool,
+	eps float64, keep func(class int) bool, flip bool, result *Mesh) {
+
+	add := func(v [3]vec3d, color *Color) {
+		t := Triangle{Vertices: [3]Vec3{v[0].vec3(), v[1].vec3(), v[2].vec3()}, Color: color}
+		if flip {
+			t.Vertices[1], t.Vertices[2] = t.Vertices[2], t.Vertices[1]
+		}
+		if t.Vertices[0] == t.Vertices[1] || t.Vertices[1] == t.Vertices[2] || t.Vertices[2] == t.Vertices[0] {
+			return
+		}
+		t.Normal = t.ComputeNormal()
+		result.Triangles = append(result.Triangles, t)
+	}
+
+	crossed := func(i int) bool {
+		return len(segments[i]) > 0 || touched[i]
+	}
+	edges := make(map[[2]Vec3][]int)
+	for i := range mesh.Triangles {
+		if crossed(i) {
+			continue
+		}
+		t := &mesh.Triangles[i]
+		for j := 0; j < 3; j++ {
+			key := sortedEdge(t.Vertices[j], t.Vertices[(j+1)%3])
+			edges[key] = append(edges[key], i)
+		}
+	}
+
+	visited := make([]bool, len(mesh.Triangles))
+	for start := range mesh.Triangles {
+		if visited[start] || crossed(start) {
+			continue
+		}
+		patch := []int{start}
+		visited[start] = true
+		largest, largestArea := start, float32(-1)
+		for k := 0; k < len(patch); k++ {
+			t := &mesh.Triangles[patch[k]]
+			if area := t.Area(); area > largestArea {
+				largest, largestArea = patch[k], area
+			}
+			for j := 0; j < 3; j++ {
+				for _, n := range edges[sortedEdge(t.Vertices[j], t.Vertices[(j+1)%3])] {
+					if !visited[n] {
+						visited[n] = true
+						patch = append(patch, n)
+					}
+				}
+			}
+		}
+		t := &mesh.Triangles[largest]
+		if keep(classifySurface(other, triangle64(t), 0)) {
+			for _, i := range patch {
+				add(triangle64(&mesh.Triangles[i]), mesh.Triangles[i].Color)
+			}
+		}
+	}
+
+	for i := range mesh.Triangles {
+		if !crossed(i) {
+			continue
+		}
+		t := &mesh.Triangles[i]
+		// only triangles that overlap a face of the other mesh can have pieces on its surface
+		offset := 0.0
+		if touched[i] {
+			offset = eps
+		}
+		for _, piece := range splitTriangle(triangle64(t), segments[i]) {
+			if keep(classifySurface(other, piece, offset)) {
+				add(piece, t.Color)
+			}
+		}
+	}
+}
+
+// classifySurface finds where a triangle lies relative to the other mesh by checking the winding
+// number just in front of and just behind its center. With no offset the triangle is only checked
+// at its center and is either inside or outside
+func classifySurface(other *BVH, t [3]vec3d, eps float64) int {
+	center := t[0].add(t[1]).add(t[2]).scale(1.0 / 3)
+	if eps == 0 {
+		if other.windingNumber(center) >= 0.5 {
+			return insideSurface
+		}
+		return outsideSurface
+	}
+	normal := t[1].sub(t[0]).cross(t[2].sub(t[0])).normalize()
+	front := other.windingNumber(center.add(normal.scale(eps))) >= 0.5
+	back := other.windingNumber(center.sub(normal.scale(eps))) >= 0.5
+	switch {
+	case front && back:
+		return insideSurface
+	case !front && back:
+		return sameSurface
+	case front && !back:
+		return oppositeSurface
+	}
+	return outsideSurface
+}
+
+// splitTriangle divides a triangle so that every segment lying on it becomes a chain of edges of
+// the pieces. The points are inserted one at a time and the segments are then recovered by
+// flipping the edges that cross them. The pieces keep the winding of the triangle
+func splitTriangle(corners [3]vec3d, segments [][2]vec3d) [][3]vec3d {
+	if len(segments) == 0 {
+		return [][3]vec3d{corners}
+	}
+
+	// dropping the axis the normal is closest to keeps the coordinates exact, and the order of
+	// the other two axes is chosen so the triangle stays counter-clockwise
+	n := corners[1].sub(corners[0]).cross(corners[2].sub(corners[0]))
+	normal := [3]float64{n.x, n.y, n.z}
+	axis := 0
+	for k := 1; k < 3; k++ {
+		if math.Abs(normal[k]) > math.Abs(normal[axis]) {
+			axis = k
+		}
+	}
+	u, v := (axis+1)%3, (axis+2)%3
+	if normal[axis] < 0 {
+		u, v = v, u
+	}
+	project := func(p vec3d) vec2d {
+		c := [3]float64{p.x, p.y, p.z}
+		return vec2d{c[u], c[v]}
+	}
+
+	scale := 0.0
+	for k := 0; k < 3; k++ {
+		scale = math.Max(scale, corners[k].sub(corners[(k+1)%3]).length())
+	}
+	tolerance := 1e-9 * scale
+
+	points := []vec3d{corners[0], corners[1], corners[2]}
+	flat := []vec2d{project(corners[0]), project(corners[1]), project(corners[2])}
+	index := map[vec3d]int{corners[0]: 0, corners[1]: 1, corners[2]: 2}
+	tris := [][3]int{{0, 1, 2}}
+
+	// edgeDistance is the signed distance of p from the edge ab, positive on the inside
+	edgeDistance := func(a, b int, p vec2d) float64 {
+		length := flat[b].sub(flat[a]).length()
+		if length == 0 {
+			return 0
+		}
+		return cross2D(flat[a], flat[b], p) / length
+	}
+	// neighbor finds the triangle on the other side of the edge from a to b
+	neighbor := func(a, b int) (int, int) {
+		for t, tri := range tris {
+			for k := 0; k < 3; k++ {
+				if tri[k] == b && tri[(k+1)%3] == a {
+					return t, k
+				}
+			}
+		}
+		return -1, -1
+	}
+
+	insert := func(p vec3d) int {
+		if i, ok := index[p]; ok {
+			return i
+		}
+		q := project(p)
+
+		// the point belongs to the triangle it is furthest inside of
+		best, bestScore := 0, math.Inf(-1)
+		var distances [3]float64
+		for t, tri := range tris {
+			var d [3]float64
+			score := math.Inf(1)
+			for k := 0; k < 3; k++ {
+				d[k] = edgeDistance(tri[k], tri[(k+1)%3], q)
+				score = math.Min(score, d[k])
+			}
+			if score > bestScore {
+				best, bestScore, distances = t, score, d
+			}
+		}
+		tri := tris[best]
+		near := []int{}
+		for k := 0; k < 3; k++ {
+			if distances[k] <= tolerance {
+				near = append(near, k)
+			}
+		}
+		if len(near) >= 2 {
+			// close enough to a corner to be the same point
+			k := near[0]
+			if near[1] == (k+1)%3 {
+				k = near[1]
+			} else if near[0] == 0 && near[1] == 2 {
+				k = 0
+			}
+			index[p] = tri[k]
+			return tri[k]
+		}
+
+		i := len(points)
+		points = append(points, p)
+		flat = append(flat, q)
+		index[p] = i
+		if len(near) == 0 {
+			tris[best] = [3]int{tri[0], tri[1], i}
+			tris = append(tris, [3]int{tri[1], tri[2], i}, [3]int{tri[2], tri[0], i})
+			return i
+		}
+
+		// on an edge so both triangles that share it are split
+		k := near[0]
+		a, b, c := tri[k], tri[(k+1)%3], tri[(k+2)%3]
+		other, ok := neighbor(a, b)
+		tris[best] = [3]int{a, i, c}
+		tris = append(tris, [3]int{i, b, c})
+		if other >= 0 {
+			o := tris[other]
+			d := o[(ok+2)%3]
+			tris[other] = [3]int{b, i, d}
+			tris = append(tris, [3]int{i, a, d})
+		}
+		return i
+	}
+
+	type constraint struct{ a, b int }
+	constraints := []constraint{}
+	for _, s := range segments {
+		constraints = append(constraints, constraint{insert(s[0]), insert(s[1])})
+	}
+
+	// break segments at any points that lie along them
+	chained := []constraint{}
+	for _, c := range constraints {
+		if c.a == c.b {
+			continue
+		}
+		d := flat[c.b].sub(flat[c.a])
+		length := d.length()
+		type stop struct {
+			t float64
+			i int
+		}
+		stops := []stop{}
+		for i := range flat {
+			if i == c.a || i == c.b {
+				continue
+			}
+			t := flat[i].sub(flat[c.a]).dot(d) / (length * length)
+			if t <= 0 || t >= 1 || math.Abs(cross2D(flat[c.a], flat[c.b], flat[i]))/length > tolerance {
+				continue
+			}
+			stops = append(stops, stop{t, i})
+		}
+		sort.Slice(stops, func(i, j int) bool { return stops[i].t < stops[j].t })
+		prev := c.a
+		for _, s := range stops {
+			chained = append(chained, constraint{prev, s.i})
+			prev = s.i
+		}
+		chained = append(chained, constraint{prev, c.b})
+	}
+
+	hasEdge := func(a, b int) bool {
+		for _, tri := range tris {
+			if hasDirectedEdge(tri, a, b) || hasDirectedEdge(tri, b, a) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range chained {
+		for iteration := 0; iteration < 4*len(tris)*len(tris) && !hasEdge(c.a, c.b); iteration++ {
+			flipped := false
+			for t := 0; t < len(tris) && !flipped; t++ {
+				for k := 0; k < 3 && !flipped; k++ {
+					p, q, w := tris[t][k], tris[t][(k+1)%3], tris[t][(k+2)%3]
+					if !segmentsCross2D(flat[c.a], flat[c.b], flat[p], flat[q]) {
+						continue
+					}
+					other, ok := neighbor(p, q)
+					if other < 0 {
+						continue
+					}
+					x := tris[other][(ok+2)%3]
+					// the quad must be convex for the flip to be valid
+					if orient2d(flat[p], flat[x], flat[w]) <= 0 || orient2d(flat[x], flat[q], flat[w]) <= 0 {
+						continue
+					}
+					tris[t] = [3]int{p, x, w}
+					tris[other] = [3]int{x, q, w}
+					flipped = true
+				}
+			}
+			if !flipped {
+				break
+			}
+		}
+	}
+
+	pieces := make([][3]vec3d, len(tris))
+	for t, tri := range tris {
+		pieces[t] = [3]vec3d{points[tri[0]], points[tri[1]], points[tri[2]]}
+	}
+	return pieces
+}
@@ -0,0 +1,105 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func checkBoolean(t *testing.T, name string, mesh *Mesh, err error, volume, tolerance float32) {
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	report := mesh.Analyze()
+	if !report.Watertight || report.FlippedTriangles != 0 || report.NonManifoldEdges != 0 {
+		t.Errorf("%s: expected a closed, consistently oriented result, found: %+v", name, report)
+	}
+	if !closeTo(mesh.Volume(), volume, tolerance) {
+		t.Errorf("%s: expected a volume of %v, found: %v", name, volume, mesh.Volume())
+	}
+}
+
+func TestBooleanBoxes(t *testing.T) {
+	a := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+	b := makeBoxMesh(Vec3{1, 1, 1}, Vec3{3, 3, 3})
+
+	union, err := a.Union(b)
+	checkBoolean(t, "union", union, err, 15, 1e-4)
+	difference, err := a.Difference(b)
+	checkBoolean(t, "difference", difference, err, 7, 1e-4)
+	intersection, err := a.Intersection(b)
+	checkBoolean(t, "intersection", intersection, err, 1, 1e-4)
+
+	// a box fully inside the other leaves a closed cavity
+	inner := makeBoxMesh(Vec3{0.5, 0.5, 0.5}, Vec3{1.5, 1.5, 1.5})
+	cavity, err := a.Difference(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cavity.Triangles) != 24 || !cavity.Analyze().Watertight || !closeTo(cavity.Volume(), 7, 1e-5) {
+		t.Errorf("Expected a box with a cavity of volume 7, found: %v", cavity.Volume())
+	}
+}
+
+func TestBooleanRotated(t *testing.T) {
+	a := makeBoxMesh(Vec3{-1, -1, -1}, Vec3{1, 1, 1})
+	rotation := Mat3{
+		{float32(math.Cos(0.3)), -float32(math.Sin(0.3)), 0},
+		{float32(math.Sin(0.3)), float32(math.Cos(0.3)), 0},
+		{0, 0, 1},
+	}
+	b := rotateMesh(makeBoxMesh(Vec3{-0.5, -0.5, -2}, Vec3{0.5, 0.5, 2}), rotation)
+
+	// a square hole all the way through
+	difference, err := a.Difference(b)
+	checkBoolean(t, "difference", difference, err, 8-2, 1e-4)
+	union, err := a.Union(b)
+	checkBoolean(t, "union", union, err, 8+2, 1e-4)
+}
+
+func TestBooleanFlushPocket(t *testing.T) {
+	part := makeBoxMesh(Vec3{0, 0, 0}, Vec3{4, 4, 2})
+	// the top of the cutter is flush with the top of the part
+	cutter := makeBoxMesh(Vec3{1, 1, 1}, Vec3{3, 3, 2})
+	pocket, err := part.Difference(cutter)
+	checkBoolean(t, "pocket", pocket, err, 32-4, 1e-4)
+
+	// stacked boxes that share a face merge into one
+	top := makeBoxMesh(Vec3{0, 0, 2}, Vec3{4, 4, 3})
+	union, err := part.Union(top)
+	checkBoolean(t, "stacked", union, err, 48, 1e-4)
+}
+
+func TestBooleanSpheres(t *testing.T) {
+	a := makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32)
+	b := makeSphereMesh(Vec3{0.7, 0.3, 0.2}, 0.8, 16, 32)
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intersection, err := a.Intersection(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	difference, err := a.Difference(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, m := range map[string]*Mesh{"union": union, "intersection": intersection, "difference": difference} {
+		if report := m.Analyze(); !report.Watertight {
+			t.Errorf("%s: expected a closed result, found: %+v", name, report)
+		}
+	}
+	// inclusion-exclusion holds exactly since the pieces come from the same surfaces
+	total := a.Volume() + b.Volume()
+	if !closeTo(union.Volume()+intersection.Volume(), total, 1e-4) {
+		t.Errorf("Expected union and intersection to add up to %v, found: %v", total, union.Volume()+intersection.Volume())
+	}
+	if !closeTo(difference.Volume()+intersection.Volume(), a.Volume(), 1e-4) {
+		t.Errorf("Expected difference and intersection to add up to %v", a.Volume())
+	}
+
+	open := &Mesh{Triangles: a.Triangles[1:]}
+	if _, err := open.Union(b); err != ErrOpenMesh {
+		t.Errorf("Expected ErrOpenMesh, found: %v", err)
+	}
+}
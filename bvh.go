@@ -0,0 +1,396 @@
+package meshful
+
+import (
+	"math"
+)
+
+// A BVH is a bounding volume hierarchy over the triangles of a mesh used to answer spatial
+// queries without testing every triangle. The tree is built with the surface area heuristic and
+// stored as a flat array in depth first order so traversal stays cache friendly. The mesh must not
+// be changed while the BVH is in use
+type BVH struct {
+	mesh  *Mesh
+	nodes []bvhNode
+	// triangle indices ordered so that every leaf covers a contiguous range
+	indices []int
+	// far field approximation of each node's triangles used for winding numbers
+	dipoles []bvhDipole
+}
+
+type bvhNode struct {
+	box AABB
+	// for a leaf this is the first entry in indices, for an interior node it is the index of the
+	// second child. The first child of an interior node always directly follows it
+	offset int
+	// number of triangles in a leaf, 0 for an interior node
+	count int
+}
+
+const (
+	bvhBins        = 16
+	bvhMaxLeafSize = 4
+	// relative cost of testing a triangle compared to traversing a node
+	bvhTriangleCost = 1.0
+	bvhTraverseCost = 1.0
+)
+
+// NewBVH builds a bounding volume hierarchy over the triangles of the mesh
+func NewBVH(mesh *Mesh) *BVH {
+	n := len(mesh.Triangles)
+	bvh := &BVH{mesh: mesh, indices: make([]int, n)}
+	if n == 0 {
+		return bvh
+	}
+
+	boxes := make([]AABB, n)
+	centers := make([]Vec3, n)
+	for i := range mesh.Triangles {
+		bvh.indices[i] = i
+		boxes[i] = mesh.Triangles[i].boundingBox()
+		centers[i] = boxes[i].Center()
+	}
+	bvh.nodes = make([]bvhNode, 0, 2*n/bvhMaxLeafSize+1)
+	bvh.build(boxes, centers, 0, n)
+	bvh.buildDipoles()
+	return bvh
+}
+
+// build adds the subtree for indices[start:end] and returns the index of its root node
+func (bvh *BVH) build(boxes []AABB, centers []Vec3, start, end int) int {
+	node := len(bvh.nodes)
+	bvh.nodes = append(bvh.nodes, bvhNode{})
+
+	box := boxes[bvh.indices[start]]
+	centerBox := AABB{Min: centers[bvh.indices[start]], Max: centers[bvh.indices[start]]}
+	for _, i := range bvh.indices[start+1 : end] {
+		box = box.Union(boxes[i])
+		centerBox = centerBox.Expand(centers[i])
+	}
+	bvh.nodes[node].box = box
+
+	count := end - start
+	mid := -1
+	if count > bvhMaxLeafSize {
+		mid = bvh.split(boxes, centers, start, end, box, centerBox)
+	}
+	if mid < 0 {
+		bvh.nodes[node].offset = start
+		bvh.nodes[node].count = count
+		return node
+	}
+
+	bvh.build(boxes, centers, start, mid)
+	bvh.nodes[node].offset = bvh.build(boxes, centers, mid, end)
+	return node
+}
+
+// split partitions indices[start:end] along the cheapest binned plane and returns the index of
+// the first triangle on the right side, or -1 if a leaf is cheaper than any split
+func (bvh *BVH) split(boxes []AABB, centers []Vec3, start, end int, box, centerBox AABB) int {
+	size := centerBox.Size()
+	axis := 0
+	if size.Y > size.X && size.Y >= size.Z {
+		axis = 1
+	} else if size.Z > size.X && size.Z > size.Y {
+		axis = 2
+	}
+	lo := component(centerBox.Min, axis)
+	extent := component(size, axis)
+	if extent <= 0 {
+		// every centroid is at the same spot so split the range in half
+		return (start + end) / 2
+	}
+
+	binOf := func(i int) int {
+		b := int(float32(bvhBins) * (component(centers[i], axis) - lo) / extent)
+		if b >= bvhBins {
+			b = bvhBins - 1
+		}
+		return b
+	}
+
+	var counts [bvhBins]int
+	var bins [bvhBins]AABB
+	for _, i := range bvh.indices[start:end] {
+		b := binOf(i)
+		if counts[b] == 0 {
+			bins[b] = boxes[i]
+		} else {
+			bins[b] = bins[b].Union(boxes[i])
+		}
+		counts[b]++
+	}
+
+	// sweep from the right to get the area and count of every right side
+	var rightArea [bvhBins]float64
+	var rightCount [bvhBins]int
+	var acc AABB
+	n := 0
+	for b := bvhBins - 1; b > 0; b-- {
+		if counts[b] > 0 {
+			if n == 0 {
+				acc = bins[b]
+			} else {
+				acc = acc.Union(bins[b])
+			}
+			n += counts[b]
+		}
+		rightArea[b] = surfaceArea(acc, n)
+		rightCount[b] = n
+	}
+
+	bestCost := math.Inf(1)
+	bestBin := -1
+	n = 0
+	for b := 0; b < bvhBins-1; b++ {
+		if counts[b] > 0 {
+			if n == 0 {
+				acc = bins[b]
+			} else {
+				acc = acc.Union(bins[b])
+			}
+			n += counts[b]
+		}
+		if n == 0 || rightCount[b+1] == 0 {
+			continue
+		}
+		cost := surfaceArea(acc, n)*float64(n) + rightArea[b+1]*float64(rightCount[b+1])
+		if cost < bestCost {
+			bestCost = cost
+			bestBin = b
+		}
+	}
+
+	parentArea := surfaceArea(box, 1)
+	leafCost := bvhTriangleCost * float64(end-start)
+	if bestBin < 0 || (parentArea > 0 && bvhTraverseCost+bvhTriangleCost*bestCost/parentArea >= leafCost) {
+		return -1
+	}
+
+	// partition the indices in place
+	mid := start
+	for i := start; i < end; i++ {
+		if binOf(bvh.indices[i]) <= bestBin {
+			bvh.indices[i], bvh.indices[mid] = bvh.indices[mid], bvh.indices[i]
+			mid++
+		}
+	}
+	return mid
+}
+
+// surfaceArea returns the surface area of the box, or 0 if it holds no triangles
+func surfaceArea(box AABB, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	s := toVec3d(box.Size())
+	return 2 * (s.x*s.y + s.y*s.z + s.z*s.x)
+}
+
+func component(v Vec3, axis int) float32 {
+	switch axis {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	default:
+		return v.Z
+	}
+}
+
+// QueryBox returns the indices of the triangles whose bounding boxes overlap the box
+func (bvh *BVH) QueryBox(box AABB) []int {
+	found := []int{}
+	if len(bvh.nodes) == 0 {
+		return found
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &bvh.nodes[n]
+		if !node.box.Intersects(box) {
+			continue
+		}
+		if node.count > 0 {
+			for _, i := range bvh.indices[node.offset : node.offset+node.count] {
+				if bvh.mesh.Triangles[i].boundingBox().Intersects(box) {
+					found = append(found, i)
+				}
+			}
+			continue
+		}
+		stack = append(stack, node.offset, n+1)
+	}
+	return found
+}
+
+// Nearest returns the triangle closest to the point along with the closest point on that triangle
+// and the distance to it. The last return value is false if the mesh has no triangles
+func (bvh *BVH) Nearest(point Vec3) (int, Vec3, float32, bool) {
+	if len(bvh.nodes) == 0 {
+		return -1, Vec3{}, 0, false
+	}
+	p := toVec3d(point)
+
+	best := math.Inf(1)
+	bestPoint := vec3d{}
+	nearest := -1
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &bvh.nodes[n]
+		if boxDistanceSquared(p, node.box) >= best {
+			continue
+		}
+		if node.count > 0 {
+			for _, i := range bvh.indices[node.offset : node.offset+node.count] {
+				q := closestPointOnTriangle(p, &bvh.mesh.Triangles[i])
+				if d := q.sub(p).dot(q.sub(p)); d < best {
+					best = d
+					bestPoint = q
+					nearest = i
+				}
+			}
+			continue
+		}
+
+		// visit the nearer child first so the search radius shrinks quickly
+		left, right := n+1, node.offset
+		if boxDistanceSquared(p, bvh.nodes[left].box) < boxDistanceSquared(p, bvh.nodes[right].box) {
+			left, right = right, left
+		}
+		stack = append(stack, left, right)
+	}
+	return nearest, bestPoint.vec3(), float32(math.Sqrt(best)), true
+}
+
+// rayBox returns the distance at which the ray enters the box, using the slab method
+func rayBox(origin, inv vec3d, box AABB) (float64, bool) {
+	tmin, tmax := 0.0, math.Inf(1)
+	slab := func(o, inv float64, lo, hi float32) {
+		if math.IsInf(inv, 0) {
+			// a ray parallel to the slab is either inside it everywhere or nowhere. Working with the
+			// infinite inverse would give NaN for rays in the plane of the slab
+			if o < float64(lo) || o > float64(hi) {
+				tmax = math.Inf(-1)
+			}
+			return
+		}
+		t1 := (float64(lo) - o) * inv
+		t2 := (float64(hi) - o) * inv
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+	}
+	slab(origin.x, inv.x, box.Min.X, box.Max.X)
+	slab(origin.y, inv.y, box.Min.Y, box.Max.Y)
+	slab(origin.z, inv.z, box.Min.Z, box.Max.Z)
+	return tmin, tmin <= tmax
+}
+
+// rayTriangle intersects a ray with a triangle using the Möller-Trumbore algorithm. It returns the
+// distance along the ray and the barycentric weights of the second and third vertex at the hit.
+// Hits behind the origin are ignored
+func rayTriangle(origin, dir vec3d, t *Triangle) (float64, float64, float64, bool) {
+	v0 := toVec3d(t.Vertices[0])
+	e1 := toVec3d(t.Vertices[1]).sub(v0)
+	e2 := toVec3d(t.Vertices[2]).sub(v0)
+
+	p := dir.cross(e2)
+	det := e1.dot(p)
+	if det == 0 {
+		return 0, 0, 0, false
+	}
+	inv := 1 / det
+	s := origin.sub(v0)
+	u := s.dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	q := s.cross(e1)
+	v := dir.dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+	dist := e2.dot(q) * inv
+	if dist < 0 {
+		return 0, 0, 0, false
+	}
+	return dist, u, v, true
+}
+
+// boxDistanceSquared returns the squared distance from the point to the closest point of the box
+func boxDistanceSquared(p vec3d, box AABB) float64 {
+	axis := func(v float64, lo, hi float32) float64 {
+		if v < float64(lo) {
+			return float64(lo) - v
+		}
+		if v > float64(hi) {
+			return v - float64(hi)
+		}
+		return 0
+	}
+	dx := axis(p.x, box.Min.X, box.Max.X)
+	dy := axis(p.y, box.Min.Y, box.Max.Y)
+	dz := axis(p.z, box.Min.Z, box.Max.Z)
+	return dx*dx + dy*dy + dz*dz
+}
+
+// closestPointOnTriangle returns the point of the triangle closest to p (Ericson, "Real-Time
+// Collision Detection" 5.1.5)
+func closestPointOnTriangle(p vec3d, t *Triangle) vec3d {
+	a := toVec3d(t.Vertices[0])
+	b := toVec3d(t.Vertices[1])
+	c := toVec3d(t.Vertices[2])
+	ab := b.sub(a)
+	ac := c.sub(a)
+
+	ap := p.sub(a)
+	d1 := ab.dot(ap)
+	d2 := ac.dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+
+	bp := p.sub(b)
+	d3 := ab.dot(bp)
+	d4 := ac.dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return a.add(ab.scale(d1 / (d1 - d3)))
+	}
+
+	cp := p.sub(c)
+	d5 := ab.dot(cp)
+	d6 := ac.dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return a.add(ac.scale(d2 / (d2 - d6)))
+	}
+
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return b.add(c.sub(b).scale((d4 - d3) / ((d4 - d3) + (d5 - d6))))
+	}
+
+	denom := 1 / (va + vb + vc)
+	v := vb * denom
+	w := vc * denom
+	return a.add(ab.scale(v)).add(ac.scale(w))
+}
@@ -0,0 +1,86 @@
+package meshful
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBVHRaycastMatchesBruteForce(t *testing.T) {
+	mesh := Merge(
+		makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32),
+		makeSphereMesh(Vec3{3, 0, 0}, 0.5, 8, 16),
+		makeBoxMesh(Vec3{-2, -2, -2}, Vec3{-1.5, 2, 2}),
+	)
+	bvh := NewBVH(mesh)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		origin := Vec3{r.Float32()*10 - 5, r.Float32()*10 - 5, r.Float32()*10 - 5}
+		target := Vec3{r.Float32()*4 - 1, r.Float32()*2 - 1, r.Float32()*2 - 1}
+		dir := toVec3d(target.Diff(origin)).normalize().vec3()
+
+		expected := -1
+		closest := math.Inf(1)
+		for j := range mesh.Triangles {
+			if d, _, _, ok := rayTriangle(toVec3d(origin), toVec3d(dir), &mesh.Triangles[j]); ok && d < closest {
+				closest = d
+				expected = j
+			}
+		}
+
+		hit, ok := bvh.Raycast(origin, dir)
+		if ok != (expected >= 0) || (ok && !closeTo(hit.Distance, float32(closest), 1e-4)) {
+			t.Fatalf("Ray %d: expected hit %d at %v, found %d at %v", i, expected, closest, hit.Triangle, hit.Distance)
+		}
+
+		// IntersectRay measures the distance in multiples of the direction
+		triangle, dist, ok := bvh.IntersectRay(origin, dir.Scale(2))
+		if ok != (expected >= 0) || (ok && (triangle != hit.Triangle || !closeTo(dist, float32(closest/2), 1e-4))) {
+			t.Fatalf("Ray %d: expected hit %d at %v, found %d at %v", i, expected, closest/2, triangle, dist)
+		}
+	}
+}
+
+func TestBVHQueryBoxAndNearest(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32)
+	bvh := NewBVH(mesh)
+
+	box := AABB{Min: Vec3{0.5, -0.2, -0.2}, Max: Vec3{1.2, 0.2, 0.2}}
+	found := map[int]bool{}
+	for _, i := range bvh.QueryBox(box) {
+		found[i] = true
+	}
+	for i := range mesh.Triangles {
+		if mesh.Triangles[i].boundingBox().Intersects(box) != found[i] {
+			t.Errorf("Box query disagrees with brute force for triangle %d", i)
+		}
+	}
+
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		p := Vec3{r.Float32()*4 - 2, r.Float32()*4 - 2, r.Float32()*4 - 2}
+		best := math.Inf(1)
+		for j := range mesh.Triangles {
+			q := closestPointOnTriangle(toVec3d(p), &mesh.Triangles[j])
+			best = math.Min(best, q.sub(toVec3d(p)).length())
+		}
+		_, _, dist, ok := bvh.Nearest(p)
+		if !ok || !closeTo(dist, float32(best), 1e-5) {
+			t.Errorf("Expected nearest distance %v for %v, found: %v", best, p, dist)
+		}
+	}
+}
+
+func TestBVHRayInPlaneOfBox(t *testing.T) {
+	// rays along the faces of a box lie in the planes of its BVH nodes, with either sign of zero
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+	bvh := NewBVH(mesh)
+	for _, dir := range []Vec3{{0, 0, -1}, {float32(math.Copysign(0, -1)), float32(math.Copysign(0, -1)), -1}} {
+		for _, origin := range []Vec3{{0, 1, 3}, {2, 1, 3}, {1, 2, 3}} {
+			if _, ok := bvh.Raycast(origin, dir); !ok {
+				t.Errorf("Expected the ray from %v along %v to hit the box", origin, dir)
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package meshful
+
+import (
+	"math"
+)
+
+// bvhDipole summarizes the triangles below a BVH node as a single oriented area at their center,
+// which is accurate enough for winding numbers at points far from the node (Barill et al., "Fast
+// Winding Numbers for Soups and Clouds")
+type bvhDipole struct {
+	center vec3d
+	// sum of the area weighted normals of the triangles
+	normal vec3d
+	// distance from the center to the farthest corner of the node's box
+	radius float64
+}
+
+// points closer to a node than this multiple of its radius are evaluated exactly
+const windingAccuracy = 3.0
+
+func (bvh *BVH) buildDipoles() {
+	bvh.dipoles = make([]bvhDipole, len(bvh.nodes))
+	// children always come after their parent so a reverse sweep visits children first
+	areas := make([]float64, len(bvh.nodes))
+	for n := len(bvh.nodes) - 1; n >= 0; n-- {
+		node := &bvh.nodes[n]
+		d := &bvh.dipoles[n]
+		if node.count > 0 {
+			for _, i := range bvh.indices[node.offset : node.offset+node.count] {
+				t := &bvh.mesh.Triangles[i]
+				a := toVec3d(t.Vertices[0])
+				b := toVec3d(t.Vertices[1])
+				c := toVec3d(t.Vertices[2])
+				cross := b.sub(a).cross(c.sub(a))
+				area := cross.length() / 2
+				d.normal = d.normal.add(cross.scale(0.5))
+				d.center = d.center.add(a.add(b).add(c).scale(area / 3))
+				areas[n] += area
+			}
+		} else {
+			for _, child := range []int{n + 1, node.offset} {
+				d.normal = d.normal.add(bvh.dipoles[child].normal)
+				d.center = d.center.add(bvh.dipoles[child].center.scale(areas[child]))
+				areas[n] += areas[child]
+			}
+		}
+		if areas[n] > 0 {
+			d.center = d.center.scale(1 / areas[n])
+		} else {
+			d.center = toVec3d(node.box.Center())
+		}
+		for i := 0; i < 8; i++ {
+			corner := node.box.Min
+			if i&1 != 0 {
+				corner.X = node.box.Max.X
+			}
+			if i&2 != 0 {
+				corner.Y = node.box.Max.Y
+			}
+			if i&4 != 0 {
+				corner.Z = node.box.Max.Z
+			}
+			d.radius = math.Max(d.radius, toVec3d(corner).sub(d.center).length())
+		}
+	}
+}
+
+// farField evaluates the dipole's approximation of the winding number at a point
+func (d *bvhDipole) farField(p vec3d) float64 {
+	r := d.center.sub(p)
+	dist := r.length()
+	return r.dot(d.normal) / (4 * math.Pi * dist * dist * dist)
+}
+
+// WindingNumber returns the generalized winding number of the mesh at the point. It is close to 1
+// inside a closed outward facing mesh and close to 0 outside, and degrades gracefully for meshes
+// with small holes or overlapping parts
+func (bvh *BVH) WindingNumber(point Vec3) float32 {
+	return float32(bvh.windingNumber(toVec3d(point)))
+}
+
+// windingNumber evaluates the winding number at a double precision point
+func (bvh *BVH) windingNumber(p vec3d) float64 {
+	if len(bvh.nodes) == 0 {
+		return 0
+	}
+
+	var total float64
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &bvh.nodes[n]
+		d := &bvh.dipoles[n]
+
+		// leaves hold so few triangles that evaluating them exactly is as cheap as the dipole
+		if node.count > 0 {
+			for _, i := range bvh.indices[node.offset : node.offset+node.count] {
+				total += solidAngle(p, &bvh.mesh.Triangles[i]) / (4 * math.Pi)
+			}
+			continue
+		}
+		if d.center.sub(p).length() > windingAccuracy*d.radius {
+			total += d.farField(p)
+			continue
+		}
+		stack = append(stack, node.offset, n+1)
+	}
+	return total
+}
+
+// Contains reports whether the point is inside the mesh, meaning its winding number is at least
+// one half
+func (bvh *BVH) Contains(point Vec3) bool {
+	return bvh.WindingNumber(point) >= 0.5
+}
+
+// SignedDistance returns the distance from the point to the closest point on the surface of the
+// mesh, negative when the point is inside. The closest surface point is returned as well
+func (bvh *BVH) SignedDistance(point Vec3) (float32, Vec3) {
+	_, closest, dist, ok := bvh.Nearest(point)
+	if !ok {
+		return float32(math.Inf(1)), Vec3{}
+	}
+	if bvh.Contains(point) {
+		dist = -dist
+	}
+	return dist, closest
+}
+
+// Contains reports whether the point is inside the mesh. A BVH is built for every call, so build
+// one with NewBVH and use BVH.Contains when classifying many points
+func (mesh *Mesh) Contains(point Vec3) bool {
+	return NewBVH(mesh).Contains(point)
+}
+
+// SignedDistance returns the distance from the point to the surface of the mesh, negative when the
+// point is inside. A BVH is built for every call, so build one with NewBVH and use
+// BVH.SignedDistance when querying many points
+func (mesh *Mesh) SignedDistance(point Vec3) float32 {
+	dist, _ := NewBVH(mesh).SignedDistance(point)
+	return dist
+}
+
+// solidAngle returns the signed solid angle the triangle covers when seen from p (Van Oosterom and
+// Strackee). It is positive when p is behind the triangle
+func solidAngle(p vec3d, t *Triangle) float64 {
+	a := toVec3d(t.Vertices[0]).sub(p)
+	b := toVec3d(t.Vertices[1]).sub(p)
+	c := toVec3d(t.Vertices[2]).sub(p)
+	la, lb, lc := a.length(), b.length(), c.length()
+	numerator := a.dot(b.cross(c))
+	denominator := la*lb*lc + a.dot(b)*lc + a.dot(c)*lb + b.dot(c)*la
+	return 2 * math.Atan2(numerator, denominator)
+}
@@ -0,0 +1,77 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 32, 64)
+	bvh := NewBVH(mesh)
+
+	inside := []Vec3{{0, 0, 0}, {0.9, 0, 0}, {0, -0.5, 0.5}, {0, 0, 0.95}}
+	outside := []Vec3{{1.1, 0, 0}, {5, 5, 5}, {0, 0, -1.2}, {-0.8, 0.8, 0}}
+	for _, p := range inside {
+		if !bvh.Contains(p) {
+			t.Errorf("Expected %v to be inside, winding number: %v", p, bvh.WindingNumber(p))
+		}
+	}
+	for _, p := range outside {
+		if bvh.Contains(p) {
+			t.Errorf("Expected %v to be outside, winding number: %v", p, bvh.WindingNumber(p))
+		}
+	}
+
+	if w := bvh.WindingNumber(Vec3{0.2, 0.1, 0}); !closeTo(w, 1, 0.02) {
+		t.Errorf("Expected a winding number of 1 at the center, found: %v", w)
+	}
+	if w := bvh.WindingNumber(Vec3{30, 0, 0}); !closeTo(w, 0, 1e-3) {
+		t.Errorf("Expected a winding number of 0 far away, found: %v", w)
+	}
+}
+
+func TestContainsWithHole(t *testing.T) {
+	sphere := makeSphereMesh(Vec3{0, 0, 0}, 1, 32, 64)
+	// drop a few triangles so the mesh is no longer watertight
+	mesh := &Mesh{Triangles: append(sphere.Triangles[:500], sphere.Triangles[510:]...)}
+	if mesh.Analyze().Watertight {
+		t.Fatal("Expected the mesh to have a hole")
+	}
+
+	bvh := NewBVH(mesh)
+	if !bvh.Contains(Vec3{0, 0, 0}) || !bvh.Contains(Vec3{0.5, 0.3, -0.2}) {
+		t.Errorf("Expected points inside a nearly watertight mesh to be inside")
+	}
+	if bvh.Contains(Vec3{1.5, 0, 0}) {
+		t.Errorf("Expected points outside a nearly watertight mesh to be outside")
+	}
+}
+
+func TestSignedDistance(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+	bvh := NewBVH(mesh)
+
+	cases := []struct {
+		point    Vec3
+		distance float32
+	}{
+		{Vec3{1, 1, 1}, -1},
+		{Vec3{1, 1, 1.5}, -0.5},
+		{Vec3{1, 1, 3}, 1},
+		{Vec3{3, 3, 1}, float32(math.Sqrt2)},
+	}
+	for _, c := range cases {
+		dist, closest := bvh.SignedDistance(c.point)
+		if !closeTo(dist, c.distance, 1e-5) {
+			t.Errorf("Expected signed distance %v at %v, found: %v", c.distance, c.point, dist)
+		}
+		gap := toVec3d(closest).sub(toVec3d(c.point)).length()
+		if !closeTo(float32(gap), float32(math.Abs(float64(c.distance))), 1e-5) {
+			t.Errorf("Expected the closest point to be %v away, found: %v", c.distance, gap)
+		}
+	}
+
+	if d := mesh.SignedDistance(Vec3{1, 1, 1}); !closeTo(d, -1, 1e-5) {
+		t.Errorf("Expected signed distance of -1, found: %v", d)
+	}
+}
@@ -0,0 +1,250 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrNoCut is used when a plane does not pass through a mesh
+var ErrNoCut = errors.New("Plane does not cut through the mesh")
+
+// Cut splits the mesh with a plane into the part above the plane, on the side the normal points
+// to, and the part below it. The openings left by the cut are closed with flat caps triangulated
+// from the slice contours so a watertight mesh gives two watertight halves. ErrNoCut is returned
+// if the whole mesh is on one side of the plane
+func (mesh *Mesh) Cut(plane Plane) (above, below *Mesh, err error) {
+	return mesh.cut(plane, 0, 0)
+}
+
+// cut splits the mesh like Cut. When pinRadius is positive a pocket for an alignment pin is sunk
+// pinDepth into both caps wherever there is room for one
+func (mesh *Mesh) cut(plane Plane, pinRadius, pinDepth float32) (above, below *Mesh, err error) {
+	if plane.Normal == (Vec3{}) {
+		return nil, nil, ErrInvalidPlane
+	}
+	normal := toVec3d(plane.Normal).normalize()
+	offset := normal.dot(toVec3d(plane.Origin))
+
+	above, below = &Mesh{}, &Mesh{}
+	add := func(m *Mesh, a, b, c Vec3, color *Color) {
+		if a == b || b == c || c == a {
+			// a corner sits on the plane so the piece on one side has no area
+			return
+		}
+		t := Triangle{Vertices: [3]Vec3{a, b, c}, Color: color}
+		t.Normal = t.ComputeNormal()
+		m.Triangles = append(m.Triangles, t)
+	}
+
+	for _, t := range mesh.Triangles {
+		var side [3]bool
+		count, onPlane := 0, 0
+		for i, p := range t.Vertices {
+			// vertices on the plane count as above, the same as when slicing
+			d := normal.dot(toVec3d(p)) - offset
+			side[i] = d >= 0
+			if side[i] {
+				count++
+			}
+			if d == 0 {
+				onPlane++
+			}
+		}
+		if onPlane == 3 {
+			// faces in the plane are covered by the caps
+			continue
+		}
+		if count == 3 {
+			above.Triangles = append(above.Triangles, t)
+			continue
+		}
+		if count == 0 {
+			below.Triangles = append(below.Triangles, t)
+			continue
+		}
+
+		// rotate the corners so the vertex alone on its side comes first
+		lone := 0
+		for i := range side {
+			if side[i] != side[(i+1)%3] && side[i] != side[(i+2)%3] {
+				lone = i
+			}
+		}
+		p := t.Vertices[lone]
+		q := t.Vertices[(lone+1)%3]
+		r := t.Vertices[(lone+2)%3]
+		pq := crossingPoint(p, q, normal, offset)
+		rp := crossingPoint(r, p, normal, offset)
+
+		loneSide, otherSide := below, above
+		if side[lone] {
+			loneSide, otherSide = above, below
+		}
+		add(loneSide, p, pq, rp, t.Color)
+		add(otherSide, pq, q, r, t.Color)
+		add(otherSide, pq, r, rp, t.Color)
+	}
+	if len(above.Triangles) == 0 || len(below.Triangles) == 0 {
+		return nil, nil, ErrNoCut
+	}
+
+	// the lower half is closed with the section just below the plane and the upper half with the
+	// section just above it, slicing with the plane turned over. They differ where faces lie in the
+	// plane, like the top of a step
+	lower := mesh.sliceTriangles(plane, nil)
+	upper := mesh.sliceTriangles(Plane{Origin: plane.Origin, Normal: plane.Normal.Scale(-1)}, nil)
+	if pinRadius > 0 {
+		for _, pin := range lower.addPins(NewBVH(mesh), pinRadius, pinDepth) {
+			upper.addHole(pin)
+			depth := normal.scale(float64(pinDepth)).vec3()
+			below.Triangles = append(below.Triangles, pocket(pin, depth.Scale(-1), false)...)
+			above.Triangles = append(above.Triangles, pocket(pin, depth, true)...)
+		}
+	}
+	for _, face := range lower.triangulate() {
+		add(below, face[0], face[1], face[2], nil)
+	}
+	for _, face := range upper.triangulate() {
+		add(above, face[0], face[1], face[2], nil)
+	}
+	return above, below, nil
+}
+
+// crossingPoint returns where the edge pq crosses the plane. It gives exactly the same point as
+// slicing so the caps share their vertices with the cut triangles
+func crossingPoint(p, q Vec3, normal vec3d, offset float64) Vec3 {
+	key := sortedEdge(p, q)
+	return edgePlanePoint(toVec3d(key[0]), toVec3d(key[1]), normal, offset).vec3()
+}
+
+// triangulate fills the area inside the contours of the slice and returns triangles that wind
+// counter-clockwise around the plane normal. Each hole is joined to the contour around it before
+// ear clipping
+func (s *Slice) triangulate() [][3]Vec3 {
+	triangles := [][3]Vec3{}
+	for _, polygon := range s.polygons() {
+		points, points3 := bridgeHoles(polygon)
+		faces, _ := earClip(points)
+		for _, f := range faces {
+			triangles = append(triangles, [3]Vec3{points3[f[0]], points3[f[1]], points3[f[2]]})
+		}
+	}
+	return triangles
+}
+
+// polygons groups each outer contour with the holes directly inside it. The outer contour is
+// first in each group
+func (s *Slice) polygons() [][]*Contour {
+	groups := [][]*Contour{}
+	index := make(map[int]int)
+	for i := range s.Contours {
+		if !s.Contours[i].Hole {
+			index[i] = len(groups)
+			groups = append(groups, []*Contour{&s.Contours[i]})
+		}
+	}
+	for i := range s.Contours {
+		hole := &s.Contours[i]
+		if !hole.Hole {
+			continue
+		}
+		// the parent is the smallest outer contour around the hole
+		parent, parentArea := -1, float32(math.MaxFloat32)
+		for j := range s.Contours {
+			outer := &s.Contours[j]
+			if outer.Hole || !pointInPolygon(hole.Points[0], outer.Points) {
+				continue
+			}
+			if area := outer.Area(); area < parentArea {
+				parent, parentArea = j, area
+			}
+		}
+		if parent >= 0 {
+			groups[index[parent]] = append(groups[index[parent]], hole)
+		}
+	}
+	return groups
+}
+
+// bridgeHoles joins the holes of a polygon to its outer contour with pairs of coincident edges,
+// giving a single counter-clockwise polygon that ear clipping can triangulate. The 2D and 3D
+// positions of the joined polygon are returned
+func bridgeHoles(polygon []*Contour) ([]vec2d, []Vec3) {
+	outer := polygon[0]
+	points := make([]vec2d, len(outer.Points))
+	for i, p := range outer.Points {
+		points[i] = vec2d{float64(p.X), float64(p.Y)}
+	}
+	points3 := append([]Vec3{}, outer.points3...)
+
+	// join the holes from right to left so earlier bridges do not block later ones
+	holes := append([]*Contour{}, polygon[1:]...)
+	rightmost := func(c *Contour) int {
+		best := 0
+		for i, p := range c.Points {
+			if p.X > c.Points[best].X {
+				best = i
+			}
+		}
+		return best
+	}
+	sort.Slice(holes, func(i, j int) bool {
+		return holes[i].Points[rightmost(holes[i])].X > holes[j].Points[rightmost(holes[j])].X
+	})
+
+	for h, hole := range holes {
+		m := rightmost(hole)
+		mp := vec2d{float64(hole.Points[m].X), float64(hole.Points[m].Y)}
+
+		// connect to the closest vertex that can be reached without crossing an edge
+		order := make([]int, len(points))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool {
+			return points[order[i]].sub(mp).length() < points[order[j]].sub(mp).length()
+		})
+		target := order[0]
+		for _, i := range order {
+			if bridgeVisible(mp, points[i], points, holes[h:]) {
+				target = i
+				break
+			}
+		}
+
+		joined := make([]vec2d, 0, len(points)+len(hole.Points)+2)
+		joined3 := make([]Vec3, 0, len(points)+len(hole.Points)+2)
+		joined = append(joined, points[:target+1]...)
+		joined3 = append(joined3, points3[:target+1]...)
+		for k := 0; k <= len(hole.Points); k++ {
+			i := (m + k) % len(hole.Points)
+			joined = append(joined, vec2d{float64(hole.Points[i].X), float64(hole.Points[i].Y)})
+			joined3 = append(joined3, hole.points3[i])
+		}
+		joined = append(joined, points[target:]...)
+		joined3 = append(joined3, points3[target:]...)
+		points, points3 = joined, joined3
+	}
+	return points, points3
+}
+
+// bridgeVisible reports whether the segment from a hole vertex to a polygon vertex crosses none of
+// the polygon edges or the edges of the holes that have not been joined yet
+func bridgeVisible(from, to vec2d, points []vec2d, holes []*Contour) bool {
+	for i := range points {
+		if segmentsCross2D(from, to, points[i], points[(i+1)%len(points)]) {
+			return false
+		}
+	}
+	for _, hole := range holes {
+		n := len(hole.Points)
+		for i := range hole.Points {
+			a, b := hole.Points[i], hole.Points[(i+1)%n]
+			if segmentsCross2D(from, to, vec2d{float64(a.X), float64(a.Y)}, vec2d{float64(b.X), float64(b.Y)}) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func checkHalves(t *testing.T, mesh *Mesh, plane Plane, aboveVolume, belowVolume float32) {
+	above, below, err := mesh.Cut(plane)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, half := range []*Mesh{above, below} {
+		report := half.Analyze()
+		if !report.Watertight || report.FlippedTriangles != 0 {
+			t.Errorf("Expected a watertight, consistently oriented half, found: %+v", report)
+		}
+	}
+	if !closeTo(above.Volume(), aboveVolume, 1e-4) || !closeTo(below.Volume(), belowVolume, 1e-4) {
+		t.Errorf("Expected volumes %v and %v, found: %v and %v", aboveVolume, belowVolume, above.Volume(), below.Volume())
+	}
+}
+
+func TestCutBox(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+	checkHalves(t, mesh, Plane{Origin: Vec3{0, 0, 0.5}, Normal: Vec3{0, 0, 1}}, 6, 2)
+	// the diagonal plane passes through box vertices
+	checkHalves(t, mesh, Plane{Origin: Vec3{1, 1, 1}, Normal: Vec3{1, 1, 0}}, 4, 4)
+
+	if _, _, err := mesh.Cut(Plane{Origin: Vec3{0, 0, 5}, Normal: Vec3{0, 0, 1}}); err != ErrNoCut {
+		t.Errorf("Expected ErrNoCut, found: %v", err)
+	}
+}
+
+func TestCutWithHoles(t *testing.T) {
+	// a box with a cavity so the caps have holes, and a second box beside it so they have two
+	// outer contours
+	inner := makeBoxMesh(Vec3{1, 1, 1}, Vec3{2, 2, 2})
+	for i := range inner.Triangles {
+		inner.Triangles[i].Flip()
+	}
+	mesh := Merge(makeBoxMesh(Vec3{0, 0, 0}, Vec3{3, 3, 3}), inner, makeBoxMesh(Vec3{5, 0, 0}, Vec3{6, 1, 3}))
+	checkHalves(t, mesh, Plane{Origin: Vec3{0, 0, 1.5}, Normal: Vec3{0, 0, 1}}, 13+1.5, 13+1.5)
+}
+
+func TestCutThroughFace(t *testing.T) {
+	// the plane contains the top of the step, which faces up or down depending on the side cut off
+	mesh := makeStepMesh()
+	checkClosed(t, mesh)
+	up := Plane{Origin: Vec3{0, 0, 1}, Normal: Vec3{0, 0, 1}}
+	down := Plane{Origin: Vec3{0, 0, 1}, Normal: Vec3{0, 0, -1}}
+	checkHalves(t, mesh, up, 2, 4)
+	checkHalves(t, mesh, down, 4, 2)
+
+	// the halves are the 1x2x1 block and the 2x2x1 bar, with no faces left over in the plane
+	above, below, err := mesh.Cut(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(above.SurfaceArea(), 10, 1e-4) || !closeTo(below.SurfaceArea(), 16, 1e-4) {
+		t.Errorf("Expected surface areas 10 and 16, found: %v and %v", above.SurfaceArea(), below.SurfaceArea())
+	}
+}
+
+func TestCutSphere(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32)
+	total := mesh.Volume()
+	above, below, err := mesh.Cut(Plane{Origin: Vec3{0.1, 0.2, 0}, Normal: Vec3{1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(above.Volume()+below.Volume(), total, 1e-4) {
+		t.Errorf("Expected the halves to add up to %v, found: %v", total, above.Volume()+below.Volume())
+	}
+	if !above.Analyze().Watertight || !below.Analyze().Watertight {
+		t.Errorf("Expected watertight halves")
+	}
+}
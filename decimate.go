@@ -0,0 +1,278 @@
+package meshful
+
+import (
+	"container/heap"
+	"math"
+)
+
+// DecimateOptions controls how far Decimate simplifies a mesh. Simplification stops as soon as
+// either limit is reached
+type DecimateOptions struct {
+	// stop once the mesh has at most this many triangles. 0 sets no target
+	TargetTriangles int
+
+	// largest error allowed for a collapse. The error is the square root of the quadric cost, the
+	// sum of squared distances from the merged vertex to the planes of the original faces around
+	// it, with the planes that hold boundaries in place weighted more. It bounds the distance to
+	// each of those planes but grows with their number, so it is not a distance to the surface.
+	// 0 sets no bound
+	MaxError float32
+
+	// keep the edges between triangles of different colors in place like boundary edges
+	PreserveColors bool
+}
+
+// weight of the planes that hold boundary edges in place, relative to the planes of the faces
+const boundaryWeight = 1000
+
+// Decimate returns a simplified copy of the mesh made by repeatedly collapsing the edge whose
+// collapse changes the surface the least, measured with quadric error metrics (Garland and
+// Heckbert, "Surface Simplification Using Quadric Error Metrics"). Boundary edges only collapse
+// along the boundary so open edges and holes keep their shape. Collapses that would fold a
+// triangle over or pinch the surface are skipped, and triangles keep their colors
+func (mesh *Mesh) Decimate(opts DecimateOptions) *Mesh {
+	tm := newTrimesh(newIndexedMesh(mesh))
+	if opts.TargetTriangles <= 0 && opts.MaxError <= 0 {
+		return tm.toIndexed().toMesh()
+	}
+	d := &decimator{
+		tm:       tm,
+		opts:     opts,
+		quadrics: make([]quadric, len(tm.positions)),
+		fixed:    make([]bool, len(tm.positions)),
+		versions: make([]int, len(tm.positions)),
+	}
+	d.buildQuadrics()
+
+	maxCost := math.Inf(1)
+	if opts.MaxError > 0 {
+		maxCost = float64(opts.MaxError) * float64(opts.MaxError)
+	}
+	for u := range tm.positions {
+		for _, v := range tm.neighbors(u) {
+			if u < v {
+				d.push(u, v)
+			}
+		}
+	}
+
+	triangles := tm.faceCount()
+	for d.queue.Len() > 0 && triangles > opts.TargetTriangles {
+		c := heap.Pop(&d.queue).(collapse)
+		if c.versionU != d.versions[c.u] || c.versionV != d.versions[c.v] {
+			// one of the vertices changed since this collapse was queued
+			continue
+		}
+		if c.cost > maxCost {
+			break
+		}
+		if !tm.canCollapse(c.u, c.v) || tm.flipsFaces(c.u, c.v, c.position) {
+			continue
+		}
+
+		triangles -= len(tm.edgeFaces(c.u, c.v))
+		tm.collapse(c.u, c.v, c.position)
+		d.quadrics[c.u] = d.quadrics[c.u].add(d.quadrics[c.v])
+		d.fixed[c.u] = d.fixed[c.u] || d.fixed[c.v]
+		d.versions[c.u]++
+		d.versions[c.v]++
+		for _, w := range tm.neighbors(c.u) {
+			d.push(c.u, w)
+		}
+	}
+	return tm.toIndexed().toMesh()
+}
+
+type decimator struct {
+	tm       *trimesh
+	opts     DecimateOptions
+	quadrics []quadric
+	// vertices on a boundary or color seam, which only move along those edges
+	fixed    []bool
+	versions []int
+	queue    collapseQueue
+}
+
+// buildQuadrics sums the planes of the faces around every vertex, and adds heavily weighted planes
+// perpendicular to the faces along boundary edges and color seams
+func (d *decimator) buildQuadrics() {
+	tm := d.tm
+	for _, face := range tm.faces {
+		a, b, c := tm.positions[face[0]], tm.positions[face[1]], tm.positions[face[2]]
+		normal := b.sub(a).cross(c.sub(a))
+		if normal.length() == 0 {
+			continue
+		}
+		normal = normal.normalize()
+		q := planeQuadric(normal, normal.dot(a))
+		for _, v := range face {
+			d.quadrics[v] = d.quadrics[v].add(q)
+		}
+
+		for k := 0; k < 3; k++ {
+			u, v := face[k], face[(k+1)%3]
+			if !d.featureEdge(u, v) {
+				continue
+			}
+			side := tm.positions[v].sub(tm.positions[u]).cross(normal)
+			if side.length() == 0 {
+				continue
+			}
+			side = side.normalize()
+			q := planeQuadric(side, side.dot(tm.positions[u])).scale(boundaryWeight)
+			d.quadrics[u] = d.quadrics[u].add(q)
+			d.quadrics[v] = d.quadrics[v].add(q)
+			d.fixed[u] = true
+			d.fixed[v] = true
+		}
+	}
+}
+
+// featureEdge reports whether the edge is on a boundary, is shared by more than two faces or,
+// when colors are preserved, separates faces of different colors
+func (d *decimator) featureEdge(u, v int) bool {
+	faces := d.tm.edgeFaces(u, v)
+	if len(faces) != 2 {
+		return true
+	}
+	if !d.opts.PreserveColors {
+		return false
+	}
+	a, b := d.tm.colors[faces[0]], d.tm.colors[faces[1]]
+	if a == nil || b == nil {
+		return a != b
+	}
+	return *a != *b
+}
+
+// push queues the collapse of the edge with the position and cost that suit it
+func (d *decimator) push(u, v int) {
+	tm := d.tm
+	if d.fixed[v] && !d.fixed[u] {
+		u, v = v, u
+	}
+	q := d.quadrics[u].add(d.quadrics[v])
+
+	var position vec3d
+	switch {
+	case d.fixed[u] && d.fixed[v]:
+		// two fixed vertices may only merge along the feature edge between them
+		if !d.featureEdge(u, v) {
+			return
+		}
+		position = d.bestPosition(q, u, v)
+	case d.fixed[u]:
+		position = tm.positions[u]
+	default:
+		position = d.bestPosition(q, u, v)
+	}
+
+	heap.Push(&d.queue, collapse{
+		u:        u,
+		v:        v,
+		position: position,
+		cost:     math.Max(0, q.evaluate(position)),
+		versionU: d.versions[u],
+		versionV: d.versions[v],
+	})
+}
+
+// bestPosition returns the point with the smallest error, or the best of the ends and middle of
+// the edge if the quadric has no unique minimum
+func (d *decimator) bestPosition(q quadric, u, v int) vec3d {
+	a, b := d.tm.positions[u], d.tm.positions[v]
+	if p, ok := q.minimize(); ok && p.sub(a.add(b).scale(0.5)).length() <= b.sub(a).length() {
+		return p
+	}
+	best, bestCost := a, q.evaluate(a)
+	for _, p := range []vec3d{b, a.add(b).scale(0.5)} {
+		if cost := q.evaluate(p); cost < bestCost {
+			best, bestCost = p, cost
+		}
+	}
+	return best
+}
+
+// quadric is a symmetric 4x4 matrix that measures the sum of squared distances to a set of planes.
+// The entries are stored as a², ab, ac, ad, b², bc, bd, c², cd, d² for the plane ax+by+cz+d=0
+type quadric [10]float64
+
+func planeQuadric(normal vec3d, offset float64) quadric {
+	a, b, c, d := normal.x, normal.y, normal.z, -offset
+	return quadric{a * a, a * b, a * c, a * d, b * b, b * c, b * d, c * c, c * d, d * d}
+}
+
+func (q quadric) add(o quadric) quadric {
+	for i := range q {
+		q[i] += o[i]
+	}
+	return q
+}
+
+func (q quadric) scale(s float64) quadric {
+	for i := range q {
+		q[i] *= s
+	}
+	return q
+}
+
+// evaluate returns the sum of squared distances from p to the planes
+func (q quadric) evaluate(p vec3d) float64 {
+	x, y, z := p.x, p.y, p.z
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z + q[9]
+}
+
+// minimize returns the point where the error is smallest. It fails when the planes do not pin down
+// a single point
+func (q quadric) minimize() (vec3d, bool) {
+	m := [3][3]float64{
+		{q[0], q[1], q[2]},
+		{q[1], q[4], q[5]},
+		{q[2], q[5], q[7]},
+	}
+	rhs := vec3d{-q[3], -q[6], -q[8]}
+	det := det3(m)
+	scale := m[0][0] + m[1][1] + m[2][2]
+	if math.Abs(det) <= 1e-9*scale*scale*scale {
+		return vec3d{}, false
+	}
+	// Cramer's rule
+	var solution [3]float64
+	for i := range solution {
+		r := m
+		r[0][i], r[1][i], r[2][i] = rhs.x, rhs.y, rhs.z
+		solution[i] = det3(r) / det
+	}
+	return vec3d{solution[0], solution[1], solution[2]}, true
+}
+
+func det3(m [3][3]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// collapse is a queued edge collapse of v into u
+type collapse struct {
+	u, v     int
+	position vec3d
+	cost     float64
+	// versions of the vertices when the collapse was queued
+	versionU, versionV int
+}
+
+// collapseQueue is a min-heap of collapses ordered by cost
+type collapseQueue []collapse
+
+func (q collapseQueue) Len() int            { return len(q) }
+func (q collapseQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q collapseQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *collapseQueue) Push(x interface{}) { *q = append(*q, x.(collapse)) }
+func (q *collapseQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
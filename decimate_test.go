@@ -0,0 +1,94 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+// makeGridMesh builds a flat, open square of n by n cells in the xy plane, coloring the cells
+// left of the middle red
+func makeGridMesh(n int) *Mesh {
+	red := &Color{Red: 1}
+	mesh := &Mesh{}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			x0, y0 := float32(i)/float32(n), float32(j)/float32(n)
+			x1, y1 := float32(i+1)/float32(n), float32(j+1)/float32(n)
+			var color *Color
+			if i < n/2 {
+				color = red
+			}
+			for _, v := range [][3]Vec3{
+				{{x0, y0, 0}, {x1, y0, 0}, {x1, y1, 0}},
+				{{x0, y0, 0}, {x1, y1, 0}, {x0, y1, 0}},
+			} {
+				t := Triangle{Vertices: v, Color: color}
+				t.Normal = t.ComputeNormal()
+				mesh.Triangles = append(mesh.Triangles, t)
+			}
+		}
+	}
+	return mesh
+}
+
+func TestDecimateSphere(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 32, 64)
+	simplified := mesh.Decimate(DecimateOptions{TargetTriangles: 500})
+	if n := len(simplified.Triangles); n > 500 || n < 450 {
+		t.Errorf("Expected about 500 triangles, found: %d", n)
+	}
+	report := simplified.Analyze()
+	if !report.Watertight || report.FlippedTriangles != 0 || report.NonManifoldVertices != 0 {
+		t.Errorf("Expected a closed, consistently oriented result, found: %+v", report)
+	}
+	if math.Abs(float64(simplified.Volume()/mesh.Volume()-1)) > 0.03 {
+		t.Errorf("Expected the volume to stay near %v, found: %v", mesh.Volume(), simplified.Volume())
+	}
+
+	// every vertex stays close to the sphere
+	for _, tri := range simplified.Triangles {
+		for _, v := range tri.Vertices {
+			if r := toVec3d(v).length(); math.Abs(r-1) > 0.02 {
+				t.Errorf("Expected vertices near the sphere, found radius: %v", r)
+			}
+		}
+	}
+}
+
+func TestDecimateErrorBound(t *testing.T) {
+	mesh := makeGridMesh(20)
+	simplified := mesh.Decimate(DecimateOptions{MaxError: 1e-4})
+	if len(simplified.Triangles) >= len(mesh.Triangles)/4 {
+		t.Errorf("Expected a flat grid to simplify a lot, found %d triangles", len(simplified.Triangles))
+	}
+	if !closeTo(simplified.SurfaceArea(), 1, 1e-5) {
+		t.Errorf("Expected the boundary to be preserved, found area: %v", simplified.SurfaceArea())
+	}
+	box, _ := simplified.BoundingBox()
+	if box.Min != (Vec3{0, 0, 0}) || box.Max != (Vec3{1, 1, 0}) {
+		t.Errorf("Expected the corners to be preserved, found: %v", box)
+	}
+
+	// a curved surface does not simplify below the error bound
+	sphere := makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32)
+	if n := len(sphere.Decimate(DecimateOptions{MaxError: 1e-6}).Triangles); n != len(sphere.Triangles) {
+		t.Errorf("Expected no collapses within a tiny error bound, found %d of %d triangles", n, len(sphere.Triangles))
+	}
+}
+
+func TestDecimatePreserveColors(t *testing.T) {
+	mesh := makeGridMesh(20)
+	simplified := mesh.Decimate(DecimateOptions{TargetTriangles: 10, PreserveColors: true})
+	var red float32
+	for _, tri := range simplified.Triangles {
+		if tri.Color != nil {
+			red += tri.Area()
+		}
+	}
+	if !closeTo(red, 0.5, 1e-5) {
+		t.Errorf("Expected the red half to keep its area of 0.5, found: %v", red)
+	}
+	if len(simplified.Triangles) > 12 {
+		t.Errorf("Expected about 10 triangles, found: %d", len(simplified.Triangles))
+	}
+}
@@ -0,0 +1,198 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidRadius is used when a drain hole radius is not positive
+var ErrInvalidRadius = errors.New("Drain hole radius must be positive")
+
+// ErrNoCavity is used when a mesh has no inner shell to drain
+var ErrNoCavity = errors.New("Mesh has no cavity")
+
+// ErrNoDrainPath is used when a drain hole would not lead from the outside of the mesh straight
+// into a cavity
+var ErrNoDrainPath = errors.New("Drain hole does not reach a cavity")
+
+// holeSegments is the number of sides of the polygon used for drain holes
+const holeSegments = 24
+
+// A DrainHole is a hole drilled from the outside of a mesh into a cavity
+type DrainHole struct {
+	// point on the outer surface where the hole opens
+	Position Vec3
+
+	// direction the hole is drilled in. The zero vector drills straight into the surface
+	Direction Vec3
+}
+
+// DrainHoleOptions controls the holes AddDrainHoles drills
+type DrainHoleOptions struct {
+	Radius float32
+
+	// holes to drill. When empty, one hole is drilled straight down from the lowest point of every
+	// cavity
+	Holes []DrainHole
+
+	// direction that points up while printing, used to find the lowest points. The zero vector
+	// uses +z
+	Up Vec3
+}
+
+// AddDrainHoles returns a copy of a hollowed mesh with cylindrical holes drilled through its walls
+// into the cavities, so resin trapped in them can drain and the pressure inside is released. Each
+// hole ends just past the inside of the wall and is cut with Difference, so the result is a single
+// closed surface. The cavities are the shells of the mesh that face inwards, as made by Hollow
+func (mesh *Mesh) AddDrainHoles(opts DrainHoleOptions) (*Mesh, error) {
+	if opts.Radius <= 0 {
+		return nil, ErrInvalidRadius
+	}
+	if len(mesh.Triangles) == 0 {
+		return nil, ErrEmptyMesh
+	}
+
+	// shells with a negative volume are cavities
+	im := newIndexedMesh(mesh)
+	cavityOf := make([]int, len(mesh.Triangles))
+	cavities := [][]int{}
+	for _, component := range im.components(im.edgeFaces()) {
+		var volume float32
+		for _, f := range component {
+			volume += mesh.Triangles[f].SignedVolume()
+		}
+		c := -1
+		if volume < 0 {
+			c = len(cavities)
+			cavities = append(cavities, component)
+		}
+		for _, f := range component {
+			cavityOf[f] = c
+		}
+	}
+	if len(cavities) == 0 {
+		return nil, ErrNoCavity
+	}
+
+	bvh := NewBVH(mesh)
+	holes := opts.Holes
+	if len(holes) == 0 {
+		up := toVec3d(opts.Up)
+		if up.length() == 0 {
+			up = vec3d{0, 0, 1}
+		}
+		up = up.normalize()
+		for _, cavity := range cavities {
+			lowest := mesh.Triangles[cavity[0]].Vertices[0]
+			for _, f := range cavity {
+				for _, v := range mesh.Triangles[f].Vertices {
+					if toVec3d(v).dot(up) < toVec3d(lowest).dot(up) {
+						lowest = v
+					}
+				}
+			}
+			// the hole opens where a ray straight down from the cavity leaves the outer surface
+			hole, found := DrainHole{}, false
+			for _, hit := range bvh.RaycastAll(lowest, up.scale(-1).vec3()) {
+				normal := mesh.Triangles[hit.Triangle].normal64()
+				if cavityOf[hit.Triangle] < 0 && normal.dot(up) < 0 {
+					hole, found = DrainHole{Position: hit.Point, Direction: up.vec3()}, true
+					break
+				}
+			}
+			if !found {
+				return nil, ErrNoDrainPath
+			}
+			holes = append(holes, hole)
+		}
+	}
+
+	drained := mesh
+	for _, hole := range holes {
+		start, end, err := mesh.drainPath(bvh, cavityOf, hole, float64(opts.Radius))
+		if err != nil {
+			return nil, err
+		}
+		drained, err = drained.Difference(cylinderMesh(start, end, float64(opts.Radius), holeSegments))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return drained, nil
+}
+
+// drainPath returns the ends of the axis of a drain hole. It starts two radii outside the surface
+// so the hole opens cleanly on sloped surfaces and ends a radius past the inside of the wall, or
+// halfway across the cavity if that is closer
+func (mesh *Mesh) drainPath(bvh *BVH, cavityOf []int, hole DrainHole, radius float64) (vec3d, vec3d, error) {
+	position := toVec3d(hole.Position)
+	dir := toVec3d(hole.Direction)
+	if dir.length() == 0 {
+		f, _, _, ok := bvh.Nearest(hole.Position)
+		if !ok {
+			return vec3d{}, vec3d{}, ErrNoDrainPath
+		}
+		dir = mesh.Triangles[f].normal64().scale(-1)
+	}
+	if dir.length() == 0 {
+		return vec3d{}, vec3d{}, ErrNoDrainPath
+	}
+	dir = dir.normalize()
+	start := position.sub(dir.scale(2 * radius))
+
+	hits := bvh.RaycastAll(start.vec3(), dir.vec3())
+	for i, hit := range hits {
+		leaving := mesh.Triangles[hit.Triangle].normal64().dot(dir) > 0
+		if cavityOf[hit.Triangle] < 0 {
+			if leaving {
+				// the ray left the part without passing through a cavity
+				break
+			}
+			continue
+		}
+		if !leaving {
+			break
+		}
+		depth := radius
+		for _, next := range hits[i+1:] {
+			if next.Distance > hit.Distance {
+				depth = math.Min(depth, float64(next.Distance-hit.Distance)/2)
+				break
+			}
+		}
+		return start, start.add(dir.scale(float64(hit.Distance) + depth)), nil
+	}
+	return vec3d{}, vec3d{}, ErrNoDrainPath
+}
+
+// cylinderMesh returns a closed cylinder around the axis from start to end approximated by a prism
+// with the given number of sides
+func cylinderMesh(start, end vec3d, radius float64, segments int) *Mesh {
+	axis := end.sub(start)
+	u, v := planeBasis(axis)
+	bottom := make([]Vec3, segments)
+	top := make([]Vec3, segments)
+	for i := range bottom {
+		angle := 2 * math.Pi * float64(i) / float64(segments)
+		p := start.add(u.scale(radius * math.Cos(angle))).add(v.scale(radius * math.Sin(angle)))
+		bottom[i] = p.vec3()
+		top[i] = p.add(axis).vec3()
+	}
+
+	mesh := &Mesh{}
+	add := func(a, b, c Vec3) {
+		t := Triangle{Vertices: [3]Vec3{a, b, c}}
+		t.Normal = t.ComputeNormal()
+		mesh.Triangles = append(mesh.Triangles, t)
+	}
+	for i := range bottom {
+		j := (i + 1) % segments
+		add(bottom[i], bottom[j], top[j])
+		add(bottom[i], top[j], top[i])
+		if i > 0 && j > 0 {
+			add(top[0], top[i], top[j])
+			add(bottom[0], bottom[j], bottom[i])
+		}
+	}
+	return mesh
+}
@@ -0,0 +1,115 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+// removedVolume returns how much smaller drained is than mesh. Both meshes have many triangles, so
+// the volumes come from MassProperties which sums them with double precision
+func removedVolume(t *testing.T, mesh, drained *Mesh) float32 {
+	t.Helper()
+	before, err := mesh.MassProperties(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := drained.MassProperties(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return before.Volume - after.Volume
+}
+
+// wallThickness measures the wall of a hollow mesh along a ray from outside it, from where the ray
+// enters the mesh to where it enters the cavity
+func wallThickness(t *testing.T, mesh *Mesh, origin, dir Vec3) float64 {
+	t.Helper()
+	hits := NewBVH(mesh).RaycastAll(origin, dir)
+	// a ray through a vertex hits every triangle around it at the same distance
+	for _, hit := range hits {
+		if hit.Distance > hits[0].Distance+1e-4 {
+			return float64(hit.Distance - hits[0].Distance)
+		}
+	}
+	t.Fatalf("Expected the ray from %v along %v to cross a wall", origin, dir)
+	return 0
+}
+
+// checkHoleVolume compares the volume the holes removed with prisms of the hole polygon as deep as
+// the walls. That is exact for flat walls. On a sphere the wall thickens away from the hole axis by
+// (1/2R_inner - 1/2R_outer) times the squared distance, under 1% for the holes tested here
+func checkHoleVolume(t *testing.T, hollow, drained *Mesh, radius float64, walls ...float64) {
+	t.Helper()
+	var expected float64
+	for _, wall := range walls {
+		expected += float64(holeSegments) / 2 * radius * radius * math.Sin(2*math.Pi/holeSegments) * wall
+	}
+	removed := float64(removedVolume(t, hollow, drained))
+	if math.Abs(removed-expected) > 0.01*expected {
+		t.Errorf("Expected the holes to remove %v, found: %v", expected, removed)
+	}
+}
+
+func TestDrainHoleAtLowestPoint(t *testing.T) {
+	hollow, err := makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32).Hollow(0.2, OffsetOptions{CellSize: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drained, err := hollow.AddDrainHoles(DrainHoleOptions{Radius: 0.15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, drained)
+	if len(drained.Shells(EdgeConnectivity)) != 1 {
+		t.Errorf("Expected the hole to join the cavity to the outside")
+	}
+	// the hole goes up through the bottom of the wall below the lowest point of the cavity, whose
+	// vertices are the ones inside the outer sphere
+	lowest := Vec3{0, 0, 0}
+	for _, tri := range hollow.Triangles {
+		for _, v := range tri.Vertices {
+			if toVec3d(v).length() < 0.9 && v.Z < lowest.Z {
+				lowest = v
+			}
+		}
+	}
+	wall := wallThickness(t, hollow, Vec3{lowest.X, lowest.Y, -2}, Vec3{0, 0, 1})
+	checkHoleVolume(t, hollow, drained, 0.15, wall)
+	for _, tri := range drained.Triangles {
+		for _, v := range tri.Vertices {
+			if v.Z < -0.5 && math.Hypot(float64(v.X), float64(v.Y)) < 0.14 {
+				t.Fatalf("Expected the hole at the bottom to be open, found vertex: %v", v)
+			}
+		}
+	}
+}
+
+func TestDrainHolePlacedByHand(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{4, 4, 2})
+	hollow, err := mesh.Hollow(0.25, OffsetOptions{CellSize: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drained, err := hollow.AddDrainHoles(DrainHoleOptions{
+		Radius: 0.3,
+		Holes:  []DrainHole{{Position: Vec3{2, 2, 2}}, {Position: Vec3{0, 1, 1}, Direction: Vec3{1, 0, 0}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, drained)
+	top := wallThickness(t, hollow, Vec3{2, 2, 3}, Vec3{0, 0, -1})
+	side := wallThickness(t, hollow, Vec3{-1, 1, 1}, Vec3{1, 0, 0})
+	checkHoleVolume(t, hollow, drained, 0.3, top, side)
+
+	if _, err := mesh.AddDrainHoles(DrainHoleOptions{Radius: 0.3}); err != ErrNoCavity {
+		t.Errorf("Expected ErrNoCavity, found: %v", err)
+	}
+	if _, err := hollow.AddDrainHoles(DrainHoleOptions{}); err != ErrInvalidRadius {
+		t.Errorf("Expected ErrInvalidRadius, found: %v", err)
+	}
+	outside := DrainHole{Position: Vec3{2, 2, 2}, Direction: Vec3{0, 0, 1}}
+	if _, err := hollow.AddDrainHoles(DrainHoleOptions{Radius: 0.3, Holes: []DrainHole{outside}}); err != ErrNoDrainPath {
+		t.Errorf("Expected ErrNoDrainPath, found: %v", err)
+	}
+}
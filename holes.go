@@ -0,0 +1,360 @@
+package meshful
+
+import (
+	"math"
+	"sort"
+)
+
+// A Hole is a closed loop of boundary edges in a mesh
+type Hole struct {
+	// the vertices around the hole in order
+	Loop []Vec3
+	// total length of the boundary edges
+	Perimeter float32
+}
+
+// HoleFillOptions controls which holes are filled and how
+type HoleFillOptions struct {
+	// holes bounded by more than this many edges are left open. 0 fills every hole
+	MaxEdges int
+
+	// refine and smooth the patches of curved holes so they blend into the surrounding surface
+	// instead of being spanned by a minimum-area surface
+	Fair bool
+}
+
+// minimum-area triangulation is cubic in the number of edges so larger holes fall back to ear
+// clipping on their fitted plane
+const maxMinimumAreaEdges = 300
+
+// Holes returns the boundary loops of the mesh
+func (mesh *Mesh) Holes() []Hole {
+	im := newIndexedMesh(mesh)
+	holes := []Hole{}
+	for _, loop := range im.boundaryLoops(im.edgeFaces()) {
+		hole := Hole{}
+		for i, v := range loop {
+			hole.Loop = append(hole.Loop, im.vertices[v])
+			next := im.vertices[loop[(i+1)%len(loop)]]
+			hole.Perimeter += float32(toVec3d(next).sub(toVec3d(im.vertices[v])).length())
+		}
+		holes = append(holes, hole)
+	}
+	return holes
+}
+
+// FillHoles closes the boundary loops of the mesh by appending new triangles and returns the
+// number of holes that were filled. Holes that lie in a plane are triangulated with ear clipping
+// in that plane, curved holes get a minimum-area triangulation which is optionally refined and
+// faired. The new triangles follow the winding of the surrounding triangles
+func (mesh *Mesh) FillHoles(opts HoleFillOptions) int {
+	im := newIndexedMesh(mesh)
+	before := len(im.faces)
+
+	filled := 0
+	for _, loop := range im.boundaryLoops(im.edgeFaces()) {
+		if opts.MaxEdges > 0 && len(loop) > opts.MaxEdges {
+			continue
+		}
+		im.fillHole(loop, opts.Fair)
+		filled++
+	}
+
+	for _, t := range im.toMesh().Triangles[before:] {
+		mesh.Triangles = append(mesh.Triangles, t)
+	}
+	return filled
+}
+
+// fillHole appends faces that close the boundary loop and returns how many were added
+func (im *indexedMesh) fillHole(loop []int, fair bool) int {
+	before := len(im.faces)
+	if len(loop) == 3 {
+		im.addFace([3]int{loop[0], loop[1], loop[2]}, nil)
+		return 1
+	}
+
+	points := make([]vec3d, len(loop))
+	for i, v := range loop {
+		points[i] = toVec3d(im.vertices[v])
+	}
+
+	var triangles [][3]int
+	planar := isPlanarLoop(points)
+	if planar || len(loop) > maxMinimumAreaEdges {
+		var ok bool
+		triangles, ok = earClipLoop(points)
+		if !ok && len(loop) <= maxMinimumAreaEdges {
+			triangles = minimumAreaTriangulation(points)
+		}
+	} else {
+		triangles = minimumAreaTriangulation(points)
+	}
+
+	for _, t := range triangles {
+		im.addFace([3]int{loop[t[0]], loop[t[1]], loop[t[2]]}, nil)
+	}
+
+	if fair && !planar {
+		im.fairPatch(loop, before)
+	}
+	return len(im.faces) - before
+}
+
+func (im *indexedMesh) addFace(face [3]int, color *Color) {
+	im.faces = append(im.faces, face)
+	im.colors = append(im.colors, color)
+}
+
+// isPlanarLoop reports whether every point is close to the plane fitted through the loop
+func isPlanarLoop(points []vec3d) bool {
+	center, normal := fitPlane(points)
+	diagonal := 0.0
+	for _, p := range points {
+		diagonal = math.Max(diagonal, p.sub(center).length())
+	}
+	for _, p := range points {
+		if math.Abs(p.sub(center).dot(normal)) > diagonal*1e-3 {
+			return false
+		}
+	}
+	return true
+}
+
+// fitPlane returns the centroid and the normal of the least squares plane through the points.
+// The normal is oriented so that the points wind counter-clockwise around it
+func fitPlane(points []vec3d) (vec3d, vec3d) {
+	center := vec3d{}
+	for _, p := range points {
+		center = center.add(p)
+	}
+	center = center.scale(1 / float64(len(points)))
+
+	// the plane normal is the direction with the smallest variance
+	frame := principalFrame(points)
+	normal := frame[2]
+
+	// Newell's method gives the winding direction of the loop
+	winding := vec3d{}
+	for i := range points {
+		a := points[i].sub(center)
+		b := points[(i+1)%len(points)].sub(center)
+		winding = winding.add(a.cross(b))
+	}
+	if winding.dot(normal) < 0 {
+		normal = normal.scale(-1)
+	}
+	return center, normal
+}
+
+// earClipLoop projects the loop onto its fitted plane and triangulates it with ear clipping
+func earClipLoop(points []vec3d) ([][3]int, bool) {
+	center, normal := fitPlane(points)
+	u, v := planeBasis(normal)
+	poly := make([]vec2d, len(points))
+	for i, p := range points {
+		d := p.sub(center)
+		poly[i] = vec2d{d.dot(u), d.dot(v)}
+	}
+	if polygonArea2D(poly) < 0 {
+		// the loop is so twisted that it winds backwards in its own plane
+		return nil, false
+	}
+	return earClip(poly)
+}
+
+// fairPatch refines the faces added after index first by splitting large triangles at their
+// centroids and then smooths the new interior vertices so the patch blends into the surface
+func (im *indexedMesh) fairPatch(loop []int, first int) {
+	// target edge length is the average length of the boundary edges
+	var target float64
+	for i := range loop {
+		a := toVec3d(im.vertices[loop[i]])
+		b := toVec3d(im.vertices[loop[(i+1)%len(loop)]])
+		target += b.sub(a).length()
+	}
+	target /= float64(len(loop))
+	targetArea := target * target * math.Sqrt(3) / 4
+
+	interior := make(map[int]bool)
+	for pass := 0; pass < 10; pass++ {
+		split := false
+		for f := first; f < len(im.faces); f++ {
+			face := im.faces[f]
+			a := toVec3d(im.vertices[face[0]])
+			b := toVec3d(im.vertices[face[1]])
+			c := toVec3d(im.vertices[face[2]])
+			if b.sub(a).cross(c.sub(a)).length()/2 <= 2*targetArea {
+				continue
+			}
+			im.vertices = append(im.vertices, a.add(b).add(c).scale(1.0/3).vec3())
+			m := len(im.vertices) - 1
+			interior[m] = true
+			im.faces[f] = [3]int{face[0], face[1], m}
+			im.addFace([3]int{face[1], face[2], m}, nil)
+			im.addFace([3]int{face[2], face[0], m}, nil)
+			split = true
+		}
+		im.relaxPatch(first)
+		if !split {
+			break
+		}
+	}
+
+	// solve for a thin plate surface by iterating the bi-Laplacian umbrella operator over the
+	// interior vertices (Kobbelt). The loop vertices take part through their full neighborhood
+	// so the patch meets the surrounding surface smoothly
+	neighbors := make(map[int][]int)
+	involved := make(map[int]bool)
+	for _, v := range loop {
+		involved[v] = true
+	}
+	for v := range interior {
+		involved[v] = true
+	}
+	seen := make(map[[2]int]bool)
+	for _, face := range im.faces {
+		for j := 0; j < 3; j++ {
+			a, b := face[j], face[(j+1)%3]
+			key := edgeKey(a, b)
+			if seen[key] || (!involved[a] && !involved[b]) {
+				continue
+			}
+			seen[key] = true
+			neighbors[a] = append(neighbors[a], b)
+			neighbors[b] = append(neighbors[b], a)
+		}
+	}
+
+	umbrella := func(v int, positions map[int]vec3d) vec3d {
+		sum := vec3d{}
+		for _, n := range neighbors[v] {
+			sum = sum.add(positions[n])
+		}
+		return sum.scale(1 / float64(len(neighbors[v]))).sub(positions[v])
+	}
+
+	positions := make(map[int]vec3d)
+	for v := range involved {
+		positions[v] = toVec3d(im.vertices[v])
+		for _, n := range neighbors[v] {
+			positions[n] = toVec3d(im.vertices[n])
+		}
+	}
+	order := sortedKeys(interior)
+	loopOrder := append([]int{}, loop...)
+	u := make(map[int]vec3d)
+	for iteration := 0; iteration < 500; iteration++ {
+		for _, v := range loopOrder {
+			u[v] = umbrella(v, positions)
+		}
+		for _, v := range order {
+			u[v] = umbrella(v, positions)
+		}
+		for _, v := range order {
+			var sum vec3d
+			var inverse float64
+			for _, n := range neighbors[v] {
+				sum = sum.add(u[n])
+				inverse += 1 / float64(len(neighbors[n]))
+			}
+			n := float64(len(neighbors[v]))
+			u2 := sum.scale(1 / n).sub(u[v])
+			nu := 1 + inverse/n
+			positions[v] = positions[v].sub(u2.scale(1 / nu))
+		}
+	}
+	for _, v := range order {
+		im.vertices[v] = positions[v].vec3()
+	}
+}
+
+// relaxPatch flips edges between faces added after index first when the two angles opposite
+// the edge sum to more than 180 degrees, as in a Delaunay flip, which removes the slivers created
+// by centroid splits
+func (im *indexedMesh) relaxPatch(first int) {
+	// edges outside of the patch must not be created again by a flip
+	outside := make(map[[2]int]bool)
+	for _, face := range im.faces[:first] {
+		for j := 0; j < 3; j++ {
+			outside[edgeKey(face[j], face[(j+1)%3])] = true
+		}
+	}
+
+	budget := 10 * (len(im.faces) - first)
+	for flips := 0; flips < budget; flips++ {
+		edges := make(map[[2]int][]int)
+		for f := first; f < len(im.faces); f++ {
+			face := im.faces[f]
+			for j := 0; j < 3; j++ {
+				key := edgeKey(face[j], face[(j+1)%3])
+				edges[key] = append(edges[key], f)
+			}
+		}
+
+		flipped := false
+		for _, key := range sortedEdgeKeys(edges) {
+			faces := edges[key]
+			if len(faces) != 2 {
+				continue
+			}
+			f, g := faces[0], faces[1]
+			a, b := key[0], key[1]
+			if !hasDirectedEdge(im.faces[f], a, b) {
+				f, g = g, f
+			}
+			if !hasDirectedEdge(im.faces[f], a, b) || !hasDirectedEdge(im.faces[g], b, a) {
+				continue
+			}
+			c := oppositeVertex(im.faces[f], a, b)
+			d := oppositeVertex(im.faces[g], a, b)
+			if c == d || outside[edgeKey(c, d)] || edges[edgeKey(c, d)] != nil {
+				continue
+			}
+
+			pa := toVec3d(im.vertices[a])
+			pb := toVec3d(im.vertices[b])
+			pc := toVec3d(im.vertices[c])
+			pd := toVec3d(im.vertices[d])
+			if cornerAngle(pc, pa, pb)+cornerAngle(pd, pa, pb) <= math.Pi+1e-9 {
+				continue
+			}
+
+			// the flipped faces must face the same way as before, which fails for a concave quad
+			before := pb.sub(pa).cross(pc.sub(pa)).add(pa.sub(pb).cross(pd.sub(pb)))
+			n1 := pa.sub(pc).cross(pd.sub(pc))
+			n2 := pb.sub(pd).cross(pc.sub(pd))
+			if n1.dot(before) <= 0 || n2.dot(before) <= 0 {
+				continue
+			}
+
+			im.faces[f] = [3]int{c, a, d}
+			im.faces[g] = [3]int{d, b, c}
+			flipped = true
+			// the edge map is stale now so start again
+			break
+		}
+		if !flipped {
+			return
+		}
+	}
+}
+
+// oppositeVertex returns the vertex of the face that is not a or b
+func oppositeVertex(face [3]int, a, b int) int {
+	for _, v := range face {
+		if v != a && v != b {
+			return v
+		}
+	}
+	return -1
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEarClipConcavePolygon(t *testing.T) {
+	// an L shaped polygon
+	poly := []vec2d{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+	triangles, ok := earClip(poly)
+	if !ok || len(triangles) != 4 {
+		t.Fatalf("Expected 4 triangles, found: %v", triangles)
+	}
+
+	var area float64
+	for _, tri := range triangles {
+		a := polygonArea2D([]vec2d{poly[tri[0]], poly[tri[1]], poly[tri[2]]})
+		if a <= 0 {
+			t.Errorf("Expected counter-clockwise triangle, found: %v", tri)
+		}
+		area += a
+	}
+	if math.Abs(area-3) > 1e-9 {
+		t.Errorf("Expected triangles to cover an area of 3, found: %v", area)
+	}
+}
+
+func TestFillPlanarHole(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	// remove the top face
+	mesh.Triangles = append(mesh.Triangles[:2], mesh.Triangles[4:]...)
+
+	holes := mesh.Holes()
+	if len(holes) != 1 || len(holes[0].Loop) != 4 || !closeTo(holes[0].Perimeter, 4, 1e-6) {
+		t.Fatalf("Expected a single square hole, found: %+v", holes)
+	}
+
+	if filled := mesh.FillHoles(HoleFillOptions{}); filled != 1 {
+		t.Errorf("Expected 1 hole to be filled, found: %d", filled)
+	}
+	checkClosed(t, mesh)
+	if !closeTo(mesh.Volume(), 1, 1e-5) {
+		t.Errorf("Expected volume of 1, found: %v", mesh.Volume())
+	}
+}
+
+func TestFillCurvedHoleWithFairing(t *testing.T) {
+	sphere := makeSphereMesh(Vec3{0, 0, 0}, 1, 24, 48)
+	full := sphere.Volume()
+
+	for _, fair := range []bool{false, true} {
+		mesh := &Mesh{}
+		// cut a patch out of the side of the sphere
+		for _, tri := range sphere.Triangles {
+			c := tri.Vertices[0].Add(tri.Vertices[1]).Add(tri.Vertices[2]).Scale(1.0 / 3)
+			if c.Diff(Vec3{1, 0, 0}).Dot(c.Diff(Vec3{1, 0, 0})) > 0.25 {
+				mesh.Triangles = append(mesh.Triangles, tri)
+			}
+		}
+		open := mesh.Volume()
+
+		if filled := mesh.FillHoles(HoleFillOptions{MaxEdges: 200, Fair: fair}); filled != 1 {
+			t.Fatalf("Expected 1 hole to be filled, found: %d", filled)
+		}
+		checkClosed(t, mesh)
+		if !mesh.Analyze().VolumeReliable {
+			t.Errorf("Expected a watertight outward facing mesh after filling")
+		}
+
+		// the faired patch bulges out to follow the sphere so it recovers more of the volume
+		volume := mesh.Volume()
+		if fair && (volume <= open || math.Abs(float64(volume-full)) > 0.01) {
+			t.Errorf("Expected faired volume close to %v, found: %v", full, volume)
+		}
+	}
+}
+
+func TestFillHolesRespectsMaxEdges(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	mesh.Triangles = append(mesh.Triangles[:2], mesh.Triangles[4:]...)
+	if filled := mesh.FillHoles(HoleFillOptions{MaxEdges: 3}); filled != 0 {
+		t.Errorf("Expected the 4 edge hole to be left open, found %d filled", filled)
+	}
+}
@@ -0,0 +1,352 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrDegenerateHull is used when the points are all coplanar or collinear and do not span a volume
+var ErrDegenerateHull = errors.New("Points do not span a volume so no 3D hull exists")
+
+// ConvexHull returns the convex hull of the mesh vertices as a closed mesh with outward facing
+// triangles
+func (mesh *Mesh) ConvexHull() (*Mesh, error) {
+	if len(mesh.Triangles) == 0 {
+		return nil, ErrEmptyMesh
+	}
+	return hullMesh(mesh.uniquePoints())
+}
+
+// ConvexHull returns the convex hull of a set of points as a closed mesh with outward facing
+// triangles. Duplicate points are ignored and points that lie on the hull surface within a small
+// tolerance are not added as vertices
+func ConvexHull(points []Vec3) (*Mesh, error) {
+	seen := make(map[Vec3]bool)
+	unique := []vec3d{}
+	for _, p := range points {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, toVec3d(p))
+		}
+	}
+	return hullMesh(unique)
+}
+
+func hullMesh(points []vec3d) (*Mesh, error) {
+	h, err := quickhull(points)
+	if err != nil {
+		return nil, err
+	}
+
+	mesh := &Mesh{}
+	for _, f := range h.faces {
+		if !f.alive {
+			continue
+		}
+		mesh.Triangles = append(mesh.Triangles, Triangle{
+			Vertices: [3]Vec3{points[f.v[0]].vec3(), points[f.v[1]].vec3(), points[f.v[2]].vec3()},
+			Normal:   f.normal.vec3(),
+		})
+	}
+	return mesh, nil
+}
+
+type hullFace struct {
+	v       [3]int
+	normal  vec3d
+	offset  float64
+	outside []int
+	alive   bool
+}
+
+func (f *hullFace) distance(p vec3d) float64 {
+	return f.normal.dot(p) - f.offset
+}
+
+// hull is the result of quickhull as indices into the input points
+type hull struct {
+	points []vec3d
+	faces  []*hullFace
+	// maps a directed edge to the face that contains it
+	edges map[[2]int]int
+	eps   float64
+}
+
+// quickhull builds the convex hull of points by starting from a tetrahedron and repeatedly adding
+// the point farthest outside of a face, replacing every face that point can see
+func quickhull(points []vec3d) (*hull, error) {
+	if len(points) < 4 {
+		return nil, ErrDegenerateHull
+	}
+
+	// tolerance scaled to the magnitude of the coordinates so float32 input that is coplanar up to
+	// rounding does not create sliver faces
+	maxCoord := 0.0
+	for _, p := range points {
+		maxCoord = math.Max(maxCoord, math.Abs(p.x)+math.Abs(p.y)+math.Abs(p.z))
+	}
+	h := &hull{points: points, edges: make(map[[2]int]int), eps: maxCoord * 1e-7}
+
+	simplex, err := h.initialSimplex()
+	if err != nil {
+		return nil, err
+	}
+
+	// orient the faces of the tetrahedron outwards
+	centroid := vec3d{}
+	for _, i := range simplex {
+		centroid = centroid.add(points[i])
+	}
+	centroid = centroid.scale(0.25)
+	faceVerts := [4][3]int{
+		{simplex[0], simplex[1], simplex[2]},
+		{simplex[0], simplex[3], simplex[1]},
+		{simplex[1], simplex[3], simplex[2]},
+		{simplex[2], simplex[3], simplex[0]},
+	}
+	for _, v := range faceVerts {
+		f := h.newFace(v)
+		if f.distance(centroid) > 0 {
+			v[1], v[2] = v[2], v[1]
+			f = h.newFace(v)
+		}
+		h.addFace(f)
+	}
+
+	// assign the remaining points to the faces they are outside of
+	remaining := []int{}
+	for i := range points {
+		if i != simplex[0] && i != simplex[1] && i != simplex[2] && i != simplex[3] {
+			remaining = append(remaining, i)
+		}
+	}
+	h.assign(remaining, h.faces)
+
+	// outside points are only ever given to new faces which are appended to the end of the list, so
+	// faces before the cursor never need to be checked again
+	for cursor := 0; cursor < len(h.faces); {
+		face := h.faces[cursor]
+		if !face.alive || len(face.outside) == 0 {
+			cursor++
+			continue
+		}
+
+		// pick the farthest point
+		eye := face.outside[0]
+		best := face.distance(points[eye])
+		for _, i := range face.outside[1:] {
+			if d := face.distance(points[i]); d > best {
+				best = d
+				eye = i
+			}
+		}
+		h.addPoint(eye, face)
+	}
+
+	return h, nil
+}
+
+// initialSimplex picks 4 points that span a tetrahedron with a large volume
+func (h *hull) initialSimplex() ([4]int, error) {
+	points := h.points
+
+	// extreme points along each axis
+	extremes := [6]int{}
+	for i, p := range points {
+		if p.x < points[extremes[0]].x {
+			extremes[0] = i
+		}
+		if p.x > points[extremes[1]].x {
+			extremes[1] = i
+		}
+		if p.y < points[extremes[2]].y {
+			extremes[2] = i
+		}
+		if p.y > points[extremes[3]].y {
+			extremes[3] = i
+		}
+		if p.z < points[extremes[4]].z {
+			extremes[4] = i
+		}
+		if p.z > points[extremes[5]].z {
+			extremes[5] = i
+		}
+	}
+
+	// the two extremes farthest apart
+	var a, b int
+	best := -1.0
+	for i := 0; i < 6; i++ {
+		for j := i + 1; j < 6; j++ {
+			d := points[extremes[i]].sub(points[extremes[j]]).length()
+			if d > best {
+				best = d
+				a, b = extremes[i], extremes[j]
+			}
+		}
+	}
+	if best <= h.eps {
+		return [4]int{}, ErrDegenerateHull
+	}
+
+	// the point farthest from the line
+	dir := points[b].sub(points[a]).normalize()
+	c := -1
+	best = h.eps
+	for i, p := range points {
+		d := p.sub(points[a]).cross(dir).length()
+		if d > best {
+			best = d
+			c = i
+		}
+	}
+	if c < 0 {
+		return [4]int{}, ErrDegenerateHull
+	}
+
+	// the point farthest from the plane
+	normal := points[b].sub(points[a]).cross(points[c].sub(points[a])).normalize()
+	d := -1
+	best = h.eps
+	for i, p := range points {
+		dist := math.Abs(p.sub(points[a]).dot(normal))
+		if dist > best {
+			best = dist
+			d = i
+		}
+	}
+	if d < 0 {
+		return [4]int{}, ErrDegenerateHull
+	}
+
+	return [4]int{a, b, c, d}, nil
+}
+
+func (h *hull) newFace(v [3]int) *hullFace {
+	a, b, c := h.points[v[0]], h.points[v[1]], h.points[v[2]]
+	normal := b.sub(a).cross(c.sub(a)).normalize()
+	return &hullFace{v: v, normal: normal, offset: normal.dot(a), alive: true}
+}
+
+func (h *hull) addFace(f *hullFace) int {
+	h.faces = append(h.faces, f)
+	index := len(h.faces) - 1
+	for i := 0; i < 3; i++ {
+		h.edges[[2]int{f.v[i], f.v[(i+1)%3]}] = index
+	}
+	return index
+}
+
+// assign gives each point to the face it is farthest outside of. Points inside every face are
+// dropped since they can never be on the hull
+func (h *hull) assign(indices []int, faces []*hullFace) {
+	for _, i := range indices {
+		var owner *hullFace
+		best := h.eps
+		for _, f := range faces {
+			if !f.alive {
+				continue
+			}
+			if d := f.distance(h.points[i]); d > best {
+				best = d
+				owner = f
+			}
+		}
+		if owner != nil {
+			owner.outside = append(owner.outside, i)
+		}
+	}
+}
+
+// addPoint adds the eye point to the hull by removing every face it can see and connecting the
+// horizon of the visible region to the point
+func (h *hull) addPoint(eye int, start *hullFace) {
+	p := h.points[eye]
+
+	// flood fill across neighbouring faces to find the visible region
+	visible := map[*hullFace]bool{start: true}
+	region := []*hullFace{start}
+	for next := 0; next < len(region); next++ {
+		f := region[next]
+		for i := 0; i < 3; i++ {
+			neighbor := h.faces[h.edges[[2]int{f.v[(i+1)%3], f.v[i]}]]
+			if !visible[neighbor] && neighbor.distance(p) > h.eps {
+				visible[neighbor] = true
+				region = append(region, neighbor)
+			}
+		}
+	}
+
+	// horizon edges are edges of visible faces whose neighbor is not visible
+	horizon := [][2]int{}
+	orphans := []int{}
+	for _, f := range region {
+		for i := 0; i < 3; i++ {
+			a, b := f.v[i], f.v[(i+1)%3]
+			neighbor := h.faces[h.edges[[2]int{b, a}]]
+			if !visible[neighbor] {
+				horizon = append(horizon, [2]int{a, b})
+			}
+		}
+		for _, i := range f.outside {
+			if i != eye {
+				orphans = append(orphans, i)
+			}
+		}
+		f.alive = false
+		f.outside = nil
+	}
+	for _, f := range region {
+		for i := 0; i < 3; i++ {
+			delete(h.edges, [2]int{f.v[i], f.v[(i+1)%3]})
+		}
+	}
+
+	newFaces := []*hullFace{}
+	for _, e := range horizon {
+		f := h.newFace([3]int{e[0], e[1], eye})
+		h.addFace(f)
+		newFaces = append(newFaces, f)
+	}
+	h.assign(orphans, newFaces)
+}
+
+// vertices returns the points that are corners of the hull
+func (h *hull) vertices() []vec3d {
+	used := make(map[int]bool)
+	vertices := []vec3d{}
+	for _, f := range h.faces {
+		if !f.alive {
+			continue
+		}
+		for _, i := range f.v {
+			if !used[i] {
+				used[i] = true
+				vertices = append(vertices, h.points[i])
+			}
+		}
+	}
+	return vertices
+}
+
+// normals returns the distinct face normals of the hull. Normals of coplanar faces and of
+// opposite faces are only returned once
+func (h *hull) normals() []vec3d {
+	seen := make(map[[3]int64]bool)
+	normals := []vec3d{}
+	for _, f := range h.faces {
+		if !f.alive || f.normal.length() == 0 {
+			continue
+		}
+		n := f.normal
+		if n.x < 0 || (n.x == 0 && n.y < 0) || (n.x == 0 && n.y == 0 && n.z < 0) {
+			n = n.scale(-1)
+		}
+		key := [3]int64{int64(math.Round(n.x * 1e6)), int64(math.Round(n.y * 1e6)), int64(math.Round(n.z * 1e6))}
+		if !seen[key] {
+			seen[key] = true
+			normals = append(normals, n)
+		}
+	}
+	return normals
+}
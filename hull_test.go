@@ -0,0 +1,78 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+// checkClosed fails the test if any edge of the mesh is not shared by exactly one other triangle
+// with the opposite direction
+func checkClosed(t *testing.T, mesh *Mesh) {
+	t.Helper()
+	edges := make(map[[2]Vec3]int)
+	for _, tri := range mesh.Triangles {
+		for i := 0; i < 3; i++ {
+			edges[[2]Vec3{tri.Vertices[i], tri.Vertices[(i+1)%3]}]++
+		}
+	}
+	for e, count := range edges {
+		if count != 1 || edges[[2]Vec3{e[1], e[0]}] != 1 {
+			t.Fatalf("Expected a closed and consistently oriented mesh, edge %v is used %d times", e, count)
+		}
+	}
+}
+
+func TestConvexHullOfBoxWithExtraPoints(t *testing.T) {
+	points := []Vec3{}
+	for _, tri := range makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2}).Triangles {
+		points = append(points, tri.Vertices[:]...)
+	}
+	// interior points, points on the faces and duplicates should not change the hull
+	points = append(points, Vec3{1, 1, 1}, Vec3{0.5, 1.5, 1}, Vec3{1, 1, 0}, Vec3{2, 1, 1}, Vec3{1, 0, 0.5}, Vec3{0, 0, 0})
+
+	hull, err := ConvexHull(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, hull)
+	if !closeTo(hull.Volume(), 8, 1e-4) {
+		t.Errorf("Expected hull volume of 8, found: %v", hull.Volume())
+	}
+	if len(hull.Triangles) != 12 {
+		t.Errorf("Expected 12 hull triangles, found: %d", len(hull.Triangles))
+	}
+}
+
+func TestConvexHullOfSpherePoints(t *testing.T) {
+	points := []Vec3{}
+	for i := 0; i < 20; i++ {
+		theta := math.Pi * (float64(i) + 0.5) / 20
+		for j := 0; j < 40; j++ {
+			phi := 2 * math.Pi * float64(j) / 40
+			points = append(points, Vec3{
+				float32(math.Sin(theta) * math.Cos(phi)),
+				float32(math.Sin(theta) * math.Sin(phi)),
+				float32(math.Cos(theta)),
+			})
+			points = append(points, points[len(points)-1].Scale(0.5))
+		}
+	}
+
+	hull, err := ConvexHull(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, hull)
+
+	volume := hull.Volume()
+	if volume <= 3.5 || volume >= 4.0*math.Pi/3 {
+		t.Errorf("Expected hull volume slightly less than the unit sphere, found: %v", volume)
+	}
+}
+
+func TestConvexHullOfFlatPoints(t *testing.T) {
+	_, err := ConvexHull([]Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}})
+	if err != ErrDegenerateHull {
+		t.Errorf("Expected ErrDegenerateHull, found: %v", err)
+	}
+}
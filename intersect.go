@@ -0,0 +1,192 @@
+package meshful
+
+import (
+	"math"
+)
+
+// triangle intersection results
+const (
+	noIntersection = iota
+	// the triangles cross along a segment, which may be a single point
+	segmentIntersection
+	// the triangles lie in the same plane
+	coplanarTriangles
+)
+
+// triangleIntersection finds where two triangles cross. The signs of each vertex relative to the
+// other triangle's plane come from exact predicates, and for crossing triangles the returned
+// segment is the overlap of the two triangles' chords along the line where their planes meet
+func triangleIntersection(a, b [3]vec3d) (int, [2]vec3d) {
+	sb := [3]int{
+		orient3d(a[0], a[1], a[2], b[0]),
+		orient3d(a[0], a[1], a[2], b[1]),
+		orient3d(a[0], a[1], a[2], b[2]),
+	}
+	if sameSide(sb) {
+		return noIntersection, [2]vec3d{}
+	}
+	if sb[0] == 0 && sb[1] == 0 && sb[2] == 0 {
+		return coplanarTriangles, [2]vec3d{}
+	}
+	sa := [3]int{
+		orient3d(b[0], b[1], b[2], a[0]),
+		orient3d(b[0], b[1], b[2], a[1]),
+		orient3d(b[0], b[1], b[2], a[2]),
+	}
+	if sameSide(sa) {
+		return noIntersection, [2]vec3d{}
+	}
+
+	na := a[1].sub(a[0]).cross(a[2].sub(a[0]))
+	nb := b[1].sub(b[0]).cross(b[2].sub(b[0]))
+	dir := na.cross(nb)
+
+	// the chord of each triangle along the intersection line
+	ca := planeChord(a, sa, nb, nb.dot(b[0]))
+	cb := planeChord(b, sb, na, na.dot(a[0]))
+
+	// overlap the chords along the line direction
+	loA, hiA := ca[0], ca[1]
+	if loA.dot(dir) > hiA.dot(dir) {
+		loA, hiA = hiA, loA
+	}
+	loB, hiB := cb[0], cb[1]
+	if loB.dot(dir) > hiB.dot(dir) {
+		loB, hiB = hiB, loB
+	}
+	lo, hi := loA, hiA
+	if loB.dot(dir) > lo.dot(dir) {
+		lo = loB
+	}
+	if hiB.dot(dir) < hi.dot(dir) {
+		hi = hiB
+	}
+	if lo.dot(dir) > hi.dot(dir) {
+		return noIntersection, [2]vec3d{}
+	}
+	return segmentIntersection, [2]vec3d{lo, hi}
+}
+
+// sameSide reports whether all signs are strictly positive or strictly negative
+func sameSide(s [3]int) bool {
+	return (s[0] > 0 && s[1] > 0 && s[2] > 0) || (s[0] < 0 && s[1] < 0 && s[2] < 0)
+}
+
+// planeChord returns the segment where triangle t crosses the plane normal.p = offset, given the
+// side of the plane each vertex is on. Both ends are the same point if the triangle only touches
+// the plane
+func planeChord(t [3]vec3d, side [3]int, normal vec3d, offset float64) [2]vec3d {
+	points := []vec3d{}
+	for i := 0; i < 3; i++ {
+		j := (i + 1) % 3
+		if side[i] == 0 {
+			points = append(points, t[i])
+		}
+		if side[i]*side[j] < 0 {
+			points = append(points, edgePlanePoint(t[i], t[j], normal, offset))
+		}
+	}
+	if len(points) == 1 {
+		return [2]vec3d{points[0], points[0]}
+	}
+	return [2]vec3d{points[0], points[1]}
+}
+
+// edgePlanePoint returns where the segment from p to q crosses the plane normal.x = offset. The
+// result does not depend on the order of p and q so adjacent triangles sharing the edge agree on
+// the point
+func edgePlanePoint(p, q vec3d, normal vec3d, offset float64) vec3d {
+	if q.x < p.x || (q.x == p.x && (q.y < p.y || (q.y == p.y && q.z < p.z))) {
+		p, q = q, p
+	}
+	dp := normal.dot(p) - offset
+	dq := normal.dot(q) - offset
+	t := dp / (dp - dq)
+	return p.add(q.sub(p).scale(t))
+}
+
+// coplanarOverlap reports whether two triangles in the same plane overlap, including touching
+func coplanarOverlap(a, b [3]vec3d) bool {
+	pa, pb := projectCoplanar(a, b)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if segmentsIntersect2D(pa[i], pa[(i+1)%3], pb[j], pb[(j+1)%3]) {
+				return true
+			}
+		}
+	}
+	return pointInTriangle2D(pa[0], pb) || pointInTriangle2D(pb[0], pa)
+}
+
+// projectCoplanar drops the axis along which the triangles' plane is steepest so that both
+// triangles can be compared in 2D. Both projected triangles are counter-clockwise
+func projectCoplanar(a, b [3]vec3d) ([3]vec2d, [3]vec2d) {
+	n := a[1].sub(a[0]).cross(a[2].sub(a[0]))
+	ax, ay, az := math.Abs(n.x), math.Abs(n.y), math.Abs(n.z)
+	project := func(p vec3d) vec2d {
+		switch {
+		case ax >= ay && ax >= az:
+			if n.x < 0 {
+				return vec2d{p.z, p.y}
+			}
+			return vec2d{p.y, p.z}
+		case ay >= az:
+			if n.y < 0 {
+				return vec2d{p.x, p.z}
+			}
+			return vec2d{p.z, p.x}
+		default:
+			if n.z < 0 {
+				return vec2d{p.y, p.x}
+			}
+			return vec2d{p.x, p.y}
+		}
+	}
+	var pa, pb [3]vec2d
+	for i := 0; i < 3; i++ {
+		pa[i] = project(a[i])
+		pb[i] = project(b[i])
+	}
+	// b may wind the other way
+	if orient2d(pa[0], pa[1], pa[2]) < 0 {
+		pa[1], pa[2] = pa[2], pa[1]
+	}
+	if orient2d(pb[0], pb[1], pb[2]) < 0 {
+		pb[1], pb[2] = pb[2], pb[1]
+	}
+	return pa, pb
+}
+
+// segmentsIntersect2D reports whether the closed segments pq and rs share a point
+func segmentsIntersect2D(p, q, r, s vec2d) bool {
+	o1 := orient2d(p, q, r)
+	o2 := orient2d(p, q, s)
+	o3 := orient2d(r, s, p)
+	o4 := orient2d(r, s, q)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	onSegment := func(a, b, c vec2d) bool {
+		return math.Min(a.x, b.x) <= c.x && c.x <= math.Max(a.x, b.x) &&
+			math.Min(a.y, b.y) <= c.y && c.y <= math.Max(a.y, b.y)
+	}
+	return (o1 == 0 && onSegment(p, q, r)) || (o2 == 0 && onSegment(p, q, s)) ||
+		(o3 == 0 && onSegment(r, s, p)) || (o4 == 0 && onSegment(r, s, q))
+}
+
+// segmentsCross2D reports whether the segments pq and rs cross at a single point that is interior
+// to both of them
+func segmentsCross2D(p, q, r, s vec2d) bool {
+	return orient2d(p, q, r)*orient2d(p, q, s) < 0 && orient2d(r, s, p)*orient2d(r, s, q) < 0
+}
+
+// pointInTriangle2D reports whether p is inside or on the counter-clockwise triangle t
+func pointInTriangle2D(p vec2d, t [3]vec2d) bool {
+	return orient2d(t[0], t[1], p) >= 0 && orient2d(t[1], t[2], p) >= 0 && orient2d(t[2], t[0], p) >= 0
+}
+
+// pointStrictlyInTriangle2D reports whether p is inside the counter-clockwise triangle t and not
+// on its boundary
+func pointStrictlyInTriangle2D(p vec2d, t [3]vec2d) bool {
+	return orient2d(t[0], t[1], p) > 0 && orient2d(t[1], t[2], p) > 0 && orient2d(t[2], t[0], p) > 0
+}
@@ -0,0 +1,67 @@
+package dxf
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/rknizzle/meshful"
+	"io"
+	"os"
+	"strconv"
+)
+
+// WriteFile writes the contours of the slices to a DXF file. Shorthand for os.Create and WriteAll
+func WriteFile(filename string, slices ...*meshful.Slice) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WriteAll(file, slices...); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteAll writes every contour of the slices as a closed polyline. The file uses the R12 entities
+// that laser cutting software reads. Each slice goes on its own layer named after its index and
+// the polylines are raised to the height of the slice plane
+func WriteAll(w io.Writer, slices ...*meshful.Slice) error {
+	bw := bufio.NewWriter(w)
+	group := func(code int, value string) {
+		fmt.Fprintf(bw, "%d\n%s\n", code, value)
+	}
+
+	group(0, "SECTION")
+	group(2, "ENTITIES")
+	for i, slice := range slices {
+		layer := "LAYER_" + strconv.Itoa(i)
+		elevation := format(slice.Plane.Elevation())
+		for _, c := range slice.Contours {
+			group(0, "POLYLINE")
+			group(8, layer)
+			// vertices follow
+			group(66, "1")
+			group(10, "0")
+			group(20, "0")
+			group(30, elevation)
+			// closed
+			group(70, "1")
+			for _, p := range c.Points {
+				group(0, "VERTEX")
+				group(8, layer)
+				group(10, format(p.X))
+				group(20, format(p.Y))
+				group(30, elevation)
+			}
+			group(0, "SEQEND")
+			group(8, layer)
+		}
+	}
+	group(0, "ENDSEC")
+	group(0, "EOF")
+	return bw.Flush()
+}
+
+func format(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
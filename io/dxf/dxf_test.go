@@ -0,0 +1,33 @@
+package dxf
+
+import (
+	"bytes"
+	"github.com/rknizzle/meshful"
+	"strings"
+	"testing"
+)
+
+// test that each contour is written as a closed polyline on its layer
+func TestWriteAll(t *testing.T) {
+	layer := func(z float32) *meshful.Slice {
+		return &meshful.Slice{
+			Plane:    meshful.Plane{Origin: meshful.Vec3{Z: z}, Normal: meshful.Vec3{Z: 2}},
+			Contours: []meshful.Contour{{Points: []meshful.Vec2{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}}},
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAll(&buf, layer(0.25), layer(0.75)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Count(out, "POLYLINE") != 2 || strings.Count(out, "VERTEX") != 6 || strings.Count(out, "SEQEND") != 2 {
+		t.Errorf("Expected 2 polylines with 3 vertices each, found:\n%s", out)
+	}
+	if !strings.Contains(out, "8\nLAYER_1\n") || !strings.Contains(out, "30\n0.75\n") {
+		t.Errorf("Expected the second layer at its elevation, found:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "0\nEOF\n") {
+		t.Errorf("Expected the file to end with EOF")
+	}
+}
@@ -66,7 +66,7 @@ func readAll(r io.Reader) (mesh *meshful.Mesh, err error) {
 		}
 	}
 
-	return &meshful.Mesh{faces}, nil
+	return &meshful.Mesh{Triangles: faces}, nil
 }
 
 // parse the line of the OBJ file into a Vec3 data structure
@@ -92,7 +92,7 @@ func parseVertex(tokens []string) (meshful.Vec3, error) {
 		return meshful.Vec3{}, err
 	}
 
-	return meshful.Vec3{float32(x), float32(y), float32(z)}, nil
+	return meshful.Vec3{X: float32(x), Y: float32(y), Z: float32(z)}, nil
 }
 
 // parse the line of the OBJ file into a Triangle data structure
@@ -120,7 +120,10 @@ func parseFace(tokens []string, vertices []meshful.Vec3) (meshful.Triangle, erro
 		faceVerts[i-1] = vertices[vertexIndex]
 	}
 
-	return meshful.Triangle{Vertices: faceVerts}, nil
+	// OBJ faces do not store a face normal so compute it from the winding order
+	triangle := meshful.Triangle{Vertices: faceVerts}
+	triangle.Normal = triangle.ComputeNormal()
+	return triangle, nil
 }
 
 func WriteFile(filename string, mesh *meshful.Mesh) error {
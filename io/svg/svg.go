@@ -0,0 +1,156 @@
+package svg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/rknizzle/meshful"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrNoLayers is used when there are no slices to write
+var ErrNoLayers = errors.New("No slices to write")
+
+// WriteFile writes the contours of a slice to an SVG file
+func WriteFile(filename string, slice *meshful.Slice) error {
+	return writeFile(filename, func(w io.Writer) error {
+		return WriteAll(w, slice)
+	})
+}
+
+// WriteAll writes the contours of a slice as a single SVG path. The drawing is sized in
+// millimeters to the bounds of the contours, with the y axis pointing up like the slice
+func WriteAll(w io.Writer, slice *meshful.Slice) error {
+	return writeDocument(w, []*meshful.Slice{slice}, false)
+}
+
+// WriteLayersFile writes every slice to one SVG file with a group per layer
+func WriteLayersFile(filename string, slices []*meshful.Slice) error {
+	return writeFile(filename, func(w io.Writer) error {
+		return WriteLayers(w, slices)
+	})
+}
+
+// WriteLayers writes every slice to one SVG document. Each slice is a group whose id is its layer
+// number and all layers share the same coordinates
+func WriteLayers(w io.Writer, slices []*meshful.Slice) error {
+	return writeDocument(w, slices, true)
+}
+
+// WriteLayerFiles writes each slice to its own SVG file. The pattern is formatted with the layer
+// number to name each file, for example "layer_%04d.svg". Every file uses the same canvas so the
+// images line up when they are shown one after another
+func WriteLayerFiles(dir, pattern string, slices []*meshful.Slice) error {
+	if len(slices) == 0 {
+		return ErrNoLayers
+	}
+	b := bounds(slices)
+	for i, slice := range slices {
+		filename := filepath.Join(dir, fmt.Sprintf(pattern, i))
+		err := writeFile(filename, func(w io.Writer) error {
+			bw := bufio.NewWriter(w)
+			writeHeader(bw, b)
+			writePath(bw, slice, b)
+			fmt.Fprintln(bw, "</svg>")
+			return bw.Flush()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(filename string, write func(w io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeDocument(w io.Writer, slices []*meshful.Slice, groups bool) error {
+	if len(slices) == 0 {
+		return ErrNoLayers
+	}
+	b := bounds(slices)
+	bw := bufio.NewWriter(w)
+	writeHeader(bw, b)
+	for i, slice := range slices {
+		if groups {
+			fmt.Fprintf(bw, "<g id=\"layer-%d\" data-z=\"%s\">\n", i, format(slice.Plane.Elevation()))
+		}
+		writePath(bw, slice, b)
+		if groups {
+			fmt.Fprintln(bw, "</g>")
+		}
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// box is the 2D extent of the contours in slice coordinates
+type box struct {
+	minX, minY, maxX, maxY float32
+}
+
+func bounds(slices []*meshful.Slice) box {
+	b := box{math.MaxFloat32, math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+	for _, slice := range slices {
+		for _, c := range slice.Contours {
+			for _, p := range c.Points {
+				b.minX = float32(math.Min(float64(b.minX), float64(p.X)))
+				b.minY = float32(math.Min(float64(b.minY), float64(p.Y)))
+				b.maxX = float32(math.Max(float64(b.maxX), float64(p.X)))
+				b.maxY = float32(math.Max(float64(b.maxY), float64(p.Y)))
+			}
+		}
+	}
+	if b.minX > b.maxX {
+		// no contours at all
+		return box{}
+	}
+	return b
+}
+
+func writeHeader(w io.Writer, b box) {
+	width, height := format(b.maxX-b.minX), format(b.maxY-b.minY)
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%smm\" height=\"%smm\" viewBox=\"0 0 %s %s\">\n",
+		width, height, width, height)
+}
+
+// writePath writes every contour of the slice as one path. The even-odd fill rule leaves the holes
+// empty, and y is flipped because SVG y points down
+func writePath(w io.Writer, slice *meshful.Slice, b box) {
+	if len(slice.Contours) == 0 {
+		return
+	}
+	fmt.Fprint(w, `<path fill="black" fill-rule="evenodd" d="`)
+	for i, c := range slice.Contours {
+		if i > 0 {
+			fmt.Fprint(w, " ")
+		}
+		for j, p := range c.Points {
+			command := "L"
+			if j == 0 {
+				command = "M"
+			}
+			fmt.Fprintf(w, "%s%s %s ", command, format(p.X-b.minX), format(b.maxY-p.Y))
+		}
+		fmt.Fprint(w, "Z")
+	}
+	fmt.Fprintln(w, `"/>`)
+}
+
+func format(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
@@ -0,0 +1,92 @@
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/rknizzle/meshful"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func square(z float32, contours ...[]meshful.Vec2) *meshful.Slice {
+	slice := &meshful.Slice{Plane: meshful.Plane{Origin: meshful.Vec3{Z: z}, Normal: meshful.Vec3{Z: 1}}}
+	for _, points := range contours {
+		slice.Contours = append(slice.Contours, meshful.Contour{Points: points})
+	}
+	return slice
+}
+
+var outer = []meshful.Vec2{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 2}}
+var hole = []meshful.Vec2{{X: 1, Y: 1}, {X: 1, Y: 1.5}, {X: 2, Y: 1.5}, {X: 2, Y: 1}}
+
+// test that a slice is written as one even-odd path with y flipped
+func TestWriteAll(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteAll(&buf, square(1, outer, hole)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		`width="4mm" height="2mm" viewBox="0 0 4 2"`,
+		`fill-rule="evenodd"`,
+		`d="M0 2 L4 2 L4 0 L0 0 Z M1 1 L1 0.5 L2 0.5 L2 1 Z"`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected the SVG to contain %q, found:\n%s", expected, out)
+		}
+	}
+}
+
+// test that multiple layers are written as groups in one document
+func TestWriteLayers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLayers(&buf, []*meshful.Slice{square(0.5, outer), square(1.5, hole)}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `<g id="layer-0" data-z="0.5">`) || !strings.Contains(out, `<g id="layer-1" data-z="1.5">`) {
+		t.Errorf("Expected a group per layer, found:\n%s", out)
+	}
+	if strings.Count(out, "<path") != 2 {
+		t.Errorf("Expected 2 paths, found:\n%s", out)
+	}
+
+	// tilted layers are labelled with their distance along the normal, the same as in DXF files
+	buf.Reset()
+	tilted := &meshful.Slice{Plane: meshful.Plane{Origin: meshful.Vec3{X: 1, Z: 1}, Normal: meshful.Vec3{X: 1, Z: 1}}}
+	if err := WriteLayers(&buf, []*meshful.Slice{tilted}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `data-z="1.4142135"`) {
+		t.Errorf("Expected the layer at its elevation, found:\n%s", buf.String())
+	}
+
+	if err := WriteLayers(&buf, nil); err != ErrNoLayers {
+		t.Errorf("Expected ErrNoLayers, found: %v", err)
+	}
+}
+
+// test that each layer is written to its own file
+func TestWriteLayerFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := WriteLayerFiles(dir, "layer_%02d.svg", []*meshful.Slice{square(0.5, outer), square(1.5, hole)}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fmt.Sprintf("layer_%02d.svg", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `viewBox="0 0 4 2"`) {
+			t.Errorf("Expected every layer to share the canvas, found:\n%s", data)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrZeroVolume is used when an operation requires a mesh that encloses a non-zero volume
+var ErrZeroVolume = errors.New("Mesh does not enclose any volume")
+
+// ErrZeroArea is used when an operation requires a mesh with a non-zero surface area
+var ErrZeroArea = errors.New("Mesh has no surface area")
+
+// MassProperties describes the mass distribution of a closed mesh with uniform density
+type MassProperties struct {
+	Volume float32
+	Mass   float32
+
+	// volume weighted center of the solid
+	CenterOfMass Vec3
+
+	// inertia tensor about the center of mass
+	Inertia Mat3
+
+	// moments of inertia about the principal axes in ascending order
+	PrincipalMoments Vec3
+
+	// the columns are the principal axes matching PrincipalMoments. They form a right-handed
+	// rotation from the principal frame into the mesh frame
+	PrincipalAxes Mat3
+}
+
+// MassProperties computes the volume, mass, center of mass and inertia tensor of the solid
+// enclosed by the mesh by integrating over the signed tetrahedra formed by each triangle and the
+// origin. A density of 0 or less is treated as a density of 1. The mesh should be closed and
+// consistently oriented for the results to be meaningful
+func (mesh *Mesh) MassProperties(density float32) (MassProperties, error) {
+	if len(mesh.Triangles) == 0 {
+		return MassProperties{}, ErrEmptyMesh
+	}
+	if density <= 0 {
+		density = 1
+	}
+
+	// integrals of 1, x, y, z, x^2, y^2, z^2, xy, yz, zx over the solid
+	var intg [10]float64
+	for _, triangle := range mesh.Triangles {
+		p0 := toVec3d(triangle.Vertices[0])
+		p1 := toVec3d(triangle.Vertices[1])
+		p2 := toVec3d(triangle.Vertices[2])
+		d := p1.sub(p0).cross(p2.sub(p0))
+
+		f1x, f2x, f3x, g0x, g1x, g2x := subexpressions(p0.x, p1.x, p2.x)
+		_, f2y, f3y, g0y, g1y, g2y := subexpressions(p0.y, p1.y, p2.y)
+		_, f2z, f3z, g0z, g1z, g2z := subexpressions(p0.z, p1.z, p2.z)
+
+		intg[0] += d.x * f1x
+		intg[1] += d.x * f2x
+		intg[2] += d.y * f2y
+		intg[3] += d.z * f2z
+		intg[4] += d.x * f3x
+		intg[5] += d.y * f3y
+		intg[6] += d.z * f3z
+		intg[7] += d.x * (p0.y*g0x + p1.y*g1x + p2.y*g2x)
+		intg[8] += d.y * (p0.z*g0y + p1.z*g1y + p2.z*g2y)
+		intg[9] += d.z * (p0.x*g0z + p1.x*g1z + p2.x*g2z)
+	}
+
+	multipliers := [10]float64{1.0 / 6, 1.0 / 24, 1.0 / 24, 1.0 / 24, 1.0 / 60, 1.0 / 60, 1.0 / 60, 1.0 / 120, 1.0 / 120, 1.0 / 120}
+	for i := range intg {
+		intg[i] *= multipliers[i]
+	}
+
+	volume := intg[0]
+	if math.Abs(volume) < 1e-30 {
+		return MassProperties{}, ErrZeroVolume
+	}
+	cm := vec3d{intg[1] / volume, intg[2] / volume, intg[3] / volume}
+
+	// inertia tensor relative to the center of mass
+	rho := float64(density)
+	ixx := rho * (intg[5] + intg[6] - volume*(cm.y*cm.y+cm.z*cm.z))
+	iyy := rho * (intg[4] + intg[6] - volume*(cm.z*cm.z+cm.x*cm.x))
+	izz := rho * (intg[4] + intg[5] - volume*(cm.x*cm.x+cm.y*cm.y))
+	ixy := -rho * (intg[7] - volume*cm.x*cm.y)
+	iyz := -rho * (intg[8] - volume*cm.y*cm.z)
+	ixz := -rho * (intg[9] - volume*cm.z*cm.x)
+	tensor := [3][3]float64{
+		{ixx, ixy, ixz},
+		{ixy, iyy, iyz},
+		{ixz, iyz, izz},
+	}
+
+	moments, axes := symmetricEigen(tensor)
+	// symmetricEigen sorts in descending order, flip to ascending and keep the frame right-handed
+	moments[0], moments[2] = moments[2], moments[0]
+	axes[0], axes[2] = axes[2], axes[0]
+	axes[2] = axes[2].scale(-1)
+
+	props := MassProperties{
+		Volume:           float32(volume),
+		Mass:             float32(volume * rho),
+		CenterOfMass:     cm.vec3(),
+		PrincipalMoments: Vec3{float32(moments[0]), float32(moments[1]), float32(moments[2])},
+		PrincipalAxes:    matFromColumns(axes[0], axes[1], axes[2]),
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			props.Inertia[i][j] = float32(tensor[i][j])
+		}
+	}
+	return props, nil
+}
+
+// CenterOfMass returns the volume weighted center of the solid enclosed by the mesh
+func (mesh *Mesh) CenterOfMass() (Vec3, error) {
+	props, err := mesh.MassProperties(1)
+	if err != nil {
+		return Vec3{}, err
+	}
+	return props.CenterOfMass, nil
+}
+
+// Centroid returns the area weighted center of the surface of the mesh. Unlike CenterOfMass it
+// does not require the mesh to be closed
+func (mesh *Mesh) Centroid() (Vec3, error) {
+	if len(mesh.Triangles) == 0 {
+		return Vec3{}, ErrEmptyMesh
+	}
+
+	var total float64
+	sum := vec3d{}
+	for _, triangle := range mesh.Triangles {
+		area := float64(triangle.Area())
+		center := toVec3d(triangle.Vertices[0]).add(toVec3d(triangle.Vertices[1])).add(toVec3d(triangle.Vertices[2])).scale(1.0 / 3)
+		sum = sum.add(center.scale(area))
+		total += area
+	}
+	if total == 0 {
+		return Vec3{}, ErrZeroArea
+	}
+	return sum.scale(1 / total).vec3(), nil
+}
+
+// subexpressions computes the polynomial terms shared by the surface integrals of one coordinate
+// over a triangle (Eberly, "Polyhedral Mass Properties")
+func subexpressions(w0, w1, w2 float64) (f1, f2, f3, g0, g1, g2 float64) {
+	temp0 := w0 + w1
+	f1 = temp0 + w2
+	temp1 := w0 * w0
+	temp2 := temp1 + w1*temp0
+	f2 = temp2 + w2*f1
+	f3 = w0*temp1 + w1*temp2 + w2*f2
+	g0 = f2 + w0*(f1+w0)
+	g1 = f2 + w1*(f1+w1)
+	g2 = f2 + w2*(f1+w2)
+	return
+}
@@ -0,0 +1,58 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func closeTo(a, b, tolerance float32) bool {
+	return math.Abs(float64(a-b)) <= float64(tolerance)
+}
+
+func TestMassPropertiesOfBox(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{1, 1, 1}, Vec3{5, 3, 2})
+
+	props, err := mesh.MassProperties(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(props.Volume, 8, 1e-4) || !closeTo(props.Mass, 16, 1e-4) {
+		t.Errorf("Expected volume 8 and mass 16, found: %v and %v", props.Volume, props.Mass)
+	}
+
+	cm := props.CenterOfMass
+	if !closeTo(cm.X, 3, 1e-4) || !closeTo(cm.Y, 2, 1e-4) || !closeTo(cm.Z, 1.5, 1e-4) {
+		t.Errorf("Expected center of mass {3 2 1.5}, found: %v", cm)
+	}
+
+	// a solid box has moments m/12 * (b^2 + c^2) about each axis
+	expected := [3]float32{16.0 / 12 * 5, 16.0 / 12 * 17, 16.0 / 12 * 20}
+	for i := 0; i < 3; i++ {
+		if !closeTo(props.Inertia[i][i], expected[i], 1e-3) {
+			t.Errorf("Expected inertia[%d][%d] of %v, found: %v", i, i, expected[i], props.Inertia[i][i])
+		}
+	}
+	if !closeTo(props.Inertia[0][1], 0, 1e-3) {
+		t.Errorf("Expected no product of inertia, found: %v", props.Inertia[0][1])
+	}
+
+	moments := props.PrincipalMoments
+	if !closeTo(moments.X, expected[0], 1e-3) || !closeTo(moments.Z, expected[2], 1e-3) {
+		t.Errorf("Expected principal moments in ascending order, found: %v", moments)
+	}
+	axis := props.PrincipalAxes.Column(0)
+	if !closeTo(float32(math.Abs(float64(axis.X))), 1, 1e-4) {
+		t.Errorf("Expected the smallest moment to be about the x axis, found: %v", axis)
+	}
+}
+
+func TestCentroid(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+	centroid, err := mesh.Centroid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(centroid.X, 1, 1e-5) || !closeTo(centroid.Y, 1, 1e-5) || !closeTo(centroid.Z, 1, 1e-5) {
+		t.Errorf("Expected centroid {1 1 1}, found: %v", centroid)
+	}
+}
@@ -0,0 +1,123 @@
+package meshful
+
+import (
+	"math"
+)
+
+// A Mat3 is a 3x3 matrix stored in row-major order
+type Mat3 [3][3]float32
+
+// Identity3 returns the 3x3 identity matrix
+func Identity3() Mat3 {
+	return Mat3{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+}
+
+// MulVec returns the product of the matrix and a column vector
+func (m Mat3) MulVec(v Vec3) Vec3 {
+	return Vec3{
+		m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// Transpose returns the matrix with its rows and columns swapped
+func (m Mat3) Transpose() Mat3 {
+	var t Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			t[i][j] = m[j][i]
+		}
+	}
+	return t
+}
+
+// Column returns the i-th column of the matrix as a vector
+func (m Mat3) Column(i int) Vec3 {
+	return Vec3{m[0][i], m[1][i], m[2][i]}
+}
+
+// matFromColumns builds a matrix whose columns are the given vectors
+func matFromColumns(a, b, c vec3d) Mat3 {
+	return Mat3{
+		{float32(a.x), float32(b.x), float32(c.x)},
+		{float32(a.y), float32(b.y), float32(c.y)},
+		{float32(a.z), float32(b.z), float32(c.z)},
+	}
+}
+
+// symmetricEigen computes the eigenvalues and unit eigenvectors of a symmetric matrix using
+// cyclic Jacobi rotations. The results are sorted by descending eigenvalue and the vectors form
+// a right-handed frame
+func symmetricEigen(a [3][3]float64) ([3]float64, [3]vec3d) {
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				// compute the rotation that zeroes a[p][q]
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < 3; k++ {
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < 3; k++ {
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < 3; k++ {
+					vkp := v[k][p]
+					vkq := v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	values := [3]float64{a[0][0], a[1][1], a[2][2]}
+	vectors := [3]vec3d{
+		{v[0][0], v[1][0], v[2][0]},
+		{v[0][1], v[1][1], v[2][1]},
+		{v[0][2], v[1][2], v[2][2]},
+	}
+
+	// sort by descending eigenvalue
+	for i := 0; i < 3; i++ {
+		for j := i + 1; j < 3; j++ {
+			if values[j] > values[i] {
+				values[i], values[j] = values[j], values[i]
+				vectors[i], vectors[j] = vectors[j], vectors[i]
+			}
+		}
+	}
+
+	vectors[0] = vectors[0].normalize()
+	vectors[1] = vectors[1].normalize()
+	vectors[2] = vectors[0].cross(vectors[1]).normalize()
+	return values, vectors
+}
@@ -1,43 +1,35 @@
 package meshful
 
+import (
+	"errors"
+)
+
+// ErrEmptyMesh is used when an operation requires a mesh with at least one triangle
+var ErrEmptyMesh = errors.New("Mesh has no triangles")
+
 // A mesh represents a collection of triangles
 type Mesh struct {
 	Triangles []Triangle
 }
 
-func (mesh *Mesh) BoundingBox() [3]float32 {
-	minX := mesh.Triangles[0].Vertices[0].X
-	maxX := mesh.Triangles[0].Vertices[0].X
-	minY := mesh.Triangles[0].Vertices[0].Y
-	maxY := mesh.Triangles[0].Vertices[0].Y
-	minZ := mesh.Triangles[0].Vertices[0].Z
-	maxZ := mesh.Triangles[0].Vertices[0].Z
+// BoundingBox returns the axis-aligned box that encloses every vertex in the mesh.
+// ErrEmptyMesh is returned if the mesh has no triangles
+func (mesh *Mesh) BoundingBox() (AABB, error) {
+	if len(mesh.Triangles) == 0 {
+		return AABB{}, ErrEmptyMesh
+	}
+
+	first := mesh.Triangles[0].Vertices[0]
+	box := AABB{Min: first, Max: first}
 
 	// loop through every vertex in the mesh
 	for _, triangle := range mesh.Triangles {
 		for _, vert := range triangle.Vertices {
-			if vert.X < minX {
-				minX = vert.X
-			}
-			if vert.X > maxX {
-				maxX = vert.X
-			}
-			if vert.Y < minY {
-				minY = vert.Y
-			}
-			if vert.Y > maxY {
-				maxY = vert.Y
-			}
-			if vert.Z < minZ {
-				minZ = vert.Z
-			}
-			if vert.Z > maxZ {
-				maxZ = vert.Z
-			}
+			box = box.Expand(vert)
 		}
 	}
 
-	return [3]float32{maxX - minX, maxY - minY, maxZ - minZ}
+	return box, nil
 }
 
 func (mesh *Mesh) Volume() float32 {
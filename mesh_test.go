@@ -1,6 +1,7 @@
 package meshful
 
 import (
+	"math"
 	"testing"
 )
 
@@ -45,16 +46,30 @@ func makeTestMesh() *Mesh {
 
 func TestBoundingBox(t *testing.T) {
 	mesh := makeTestMesh()
-	bbox := mesh.GetBoundingBox()
+	bbox, err := mesh.BoundingBox()
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if bbox != ([3]float32{1, 1, 1}) {
-		t.Errorf("Expected bounding box of [1 1 1], found: %v", bbox)
+	if bbox.Min != (Vec3{0, 0, 0}) || bbox.Max != (Vec3{1, 1, 1}) {
+		t.Errorf("Expected bounding box from {0 0 0} to {1 1 1}, found: %v", bbox)
+	}
+	if bbox.Size() != (Vec3{1, 1, 1}) {
+		t.Errorf("Expected bounding box size of {1 1 1}, found: %v", bbox.Size())
+	}
+}
+
+func TestBoundingBoxEmptyMesh(t *testing.T) {
+	mesh := &Mesh{}
+	_, err := mesh.BoundingBox()
+	if err != ErrEmptyMesh {
+		t.Errorf("Expected ErrEmptyMesh, found: %v", err)
 	}
 }
 
 func TestVolume(t *testing.T) {
 	mesh := makeTestMesh()
-	volume := mesh.GetVolume()
+	volume := mesh.Volume()
 	if volume <= 0 {
 		t.Errorf("Expected positive non-zero volume")
 	}
@@ -62,8 +77,120 @@ func TestVolume(t *testing.T) {
 
 func TestArea(t *testing.T) {
 	mesh := makeTestMesh()
-	area := mesh.GetSurfaceArea()
+	area := mesh.SurfaceArea()
 	if area <= 0 {
 		t.Errorf("Expected positive non-zero surface area")
 	}
 }
+
+// makeBoxMesh builds a closed, outward facing box between two corners
+func makeBoxMesh(min, max Vec3) *Mesh {
+	corners := [8]Vec3{
+		{min.X, min.Y, min.Z},
+		{max.X, min.Y, min.Z},
+		{max.X, max.Y, min.Z},
+		{min.X, max.Y, min.Z},
+		{min.X, min.Y, max.Z},
+		{max.X, min.Y, max.Z},
+		{max.X, max.Y, max.Z},
+		{min.X, max.Y, max.Z},
+	}
+	faces := [12][3]int{
+		{0, 2, 1}, {0, 3, 2}, // bottom
+		{4, 5, 6}, {4, 6, 7}, // top
+		{0, 1, 5}, {0, 5, 4}, // front
+		{2, 3, 7}, {2, 7, 6}, // back
+		{1, 2, 6}, {1, 6, 5}, // right
+		{3, 0, 4}, {3, 4, 7}, // left
+	}
+
+	mesh := &Mesh{}
+	for _, f := range faces {
+		a, b, c := corners[f[0]], corners[f[1]], corners[f[2]]
+		normal := b.Diff(a).Cross(c.Diff(a))
+		mesh.Triangles = append(mesh.Triangles, Triangle{
+			Vertices: [3]Vec3{a, b, c},
+			Normal:   toVec3d(normal).normalize().vec3(),
+		})
+	}
+	return mesh
+}
+
+// makeStepMesh returns an L-shaped prism two units deep along y. Its profile in the xz plane is a
+// 2x1 bar along the bottom with a 1x1 block on its left end, so the top of the bar is a step at z=1
+func makeStepMesh() *Mesh {
+	profile := [6][2]float32{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+	corner := func(i int, y float32) Vec3 {
+		p := profile[i%len(profile)]
+		return Vec3{p[0], y, p[1]}
+	}
+
+	mesh := &Mesh{}
+	add := func(a, b, c Vec3) {
+		t := Triangle{Vertices: [3]Vec3{a, b, c}}
+		t.Normal = t.ComputeNormal()
+		mesh.Triangles = append(mesh.Triangles, t)
+	}
+	// the ends are fanned from the inside corner of the L
+	for _, i := range []int{4, 5, 0, 1} {
+		add(corner(3, 0), corner(i, 0), corner(i+1, 0))
+		add(corner(3, 2), corner(i+1, 2), corner(i, 2))
+	}
+	for i := range profile {
+		add(corner(i, 0), corner(i, 2), corner(i+1, 2))
+		add(corner(i, 0), corner(i+1, 2), corner(i+1, 0))
+	}
+	return mesh
+}
+
+// rotateMesh returns a copy of the mesh with every vertex and normal multiplied by rotation
+func rotateMesh(mesh *Mesh, rotation Mat3) *Mesh {
+	rotated := &Mesh{}
+	for _, t := range mesh.Triangles {
+		for i := range t.Vertices {
+			t.Vertices[i] = rotation.MulVec(t.Vertices[i])
+		}
+		t.Normal = rotation.MulVec(t.Normal)
+		rotated.Triangles = append(rotated.Triangles, t)
+	}
+	return rotated
+}
+
+// makeSphereMesh builds a closed, outward facing UV sphere
+func makeSphereMesh(center Vec3, radius float32, rings, segments int) *Mesh {
+	vertex := func(ring, segment int) Vec3 {
+		if ring == 0 {
+			return center.Add(Vec3{0, 0, radius})
+		}
+		if ring == rings {
+			return center.Add(Vec3{0, 0, -radius})
+		}
+		theta := math.Pi * float64(ring) / float64(rings)
+		phi := 2 * math.Pi * float64(segment%segments) / float64(segments)
+		return center.Add(Vec3{
+			radius * float32(math.Sin(theta)*math.Cos(phi)),
+			radius * float32(math.Sin(theta)*math.Sin(phi)),
+			radius * float32(math.Cos(theta)),
+		})
+	}
+
+	mesh := &Mesh{}
+	add := func(a, b, c Vec3) {
+		t := Triangle{Vertices: [3]Vec3{a, b, c}}
+		t.Normal = t.ComputeNormal()
+		mesh.Triangles = append(mesh.Triangles, t)
+	}
+	for r := 0; r < rings; r++ {
+		for s := 0; s < segments; s++ {
+			a, b := vertex(r, s), vertex(r, s+1)
+			c, d := vertex(r+1, s), vertex(r+1, s+1)
+			if r != 0 {
+				add(a, c, b)
+			}
+			if r != rings-1 {
+				add(b, c, d)
+			}
+		}
+	}
+	return mesh
+}
@@ -0,0 +1,99 @@
+package meshful
+
+import (
+	"math"
+)
+
+// NormalWeighting selects how face normals are combined into vertex normals
+type NormalWeighting int
+
+const (
+	// AreaWeighted weights each face normal by the area of the face
+	AreaWeighted NormalWeighting = iota
+	// AngleWeighted weights each face normal by the angle of the face at the vertex
+	AngleWeighted
+)
+
+// ComputeNormal returns the unit normal of the triangle based on the winding order of its
+// vertices. The zero vector is returned for a degenerate triangle
+func (t *Triangle) ComputeNormal() Vec3 {
+	return t.normal64().vec3()
+}
+
+func (t *Triangle) normal64() vec3d {
+	a := toVec3d(t.Vertices[0])
+	b := toVec3d(t.Vertices[1])
+	c := toVec3d(t.Vertices[2])
+	return b.sub(a).cross(c.sub(a)).normalize()
+}
+
+// RecomputeNormals replaces the stored normal of every triangle with the normal computed from
+// its winding order
+func (mesh *Mesh) RecomputeNormals() {
+	for i := range mesh.Triangles {
+		mesh.Triangles[i].Normal = mesh.Triangles[i].ComputeNormal()
+	}
+}
+
+// VertexNormals returns a smooth normal for each corner of each triangle. Corners at the same
+// position share a normal that is the weighted average of the surrounding face normals
+func (mesh *Mesh) VertexNormals(weighting NormalWeighting) [][3]Vec3 {
+	im := newIndexedMesh(mesh)
+	sums := make([]vec3d, len(im.vertices))
+
+	for i, face := range im.faces {
+		triangle := &mesh.Triangles[i]
+		a := toVec3d(triangle.Vertices[0])
+		b := toVec3d(triangle.Vertices[1])
+		c := toVec3d(triangle.Vertices[2])
+		cross := b.sub(a).cross(c.sub(a))
+
+		if weighting == AngleWeighted {
+			n := cross.normalize()
+			corners := [3]vec3d{a, b, c}
+			for j := 0; j < 3; j++ {
+				angle := cornerAngle(corners[j], corners[(j+1)%3], corners[(j+2)%3])
+				sums[face[j]] = sums[face[j]].add(n.scale(angle))
+			}
+		} else {
+			// the length of the cross product is twice the area so it is already area weighted
+			for j := 0; j < 3; j++ {
+				sums[face[j]] = sums[face[j]].add(cross)
+			}
+		}
+	}
+
+	normals := make([][3]Vec3, len(im.faces))
+	for i, face := range im.faces {
+		for j := 0; j < 3; j++ {
+			normals[i][j] = sums[face[j]].normalize().vec3()
+		}
+	}
+	return normals
+}
+
+// InconsistentNormals returns the indices of triangles whose stored normal differs from the
+// normal computed from their winding by more than maxAngle degrees. Triangles with a zero stored
+// normal are reported as well, while degenerate triangles are skipped since they have no winding
+func (mesh *Mesh) InconsistentNormals(maxAngle float32) []int {
+	limit := math.Cos(float64(maxAngle) * math.Pi / 180)
+	indices := []int{}
+	for i := range mesh.Triangles {
+		computed := mesh.Triangles[i].normal64()
+		if computed.length() == 0 {
+			continue
+		}
+		stored := toVec3d(mesh.Triangles[i].Normal).normalize()
+		if stored.length() == 0 || stored.dot(computed) < limit {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// cornerAngle returns the angle in radians at corner p of the triangle p, q, r
+func cornerAngle(p, q, r vec3d) float64 {
+	u := q.sub(p).normalize()
+	v := r.sub(p).normalize()
+	return math.Acos(math.Max(-1, math.Min(1, u.dot(v))))
+}
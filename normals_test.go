@@ -0,0 +1,55 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestRecomputeNormals(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	expected := []Vec3{}
+	for i := range mesh.Triangles {
+		expected = append(expected, mesh.Triangles[i].Normal)
+		mesh.Triangles[i].Normal = Vec3{}
+	}
+
+	mesh.RecomputeNormals()
+	for i, tri := range mesh.Triangles {
+		if tri.Normal != expected[i] {
+			t.Errorf("Expected normal %v for triangle %d, found: %v", expected[i], i, tri.Normal)
+		}
+	}
+}
+
+func TestVertexNormals(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+
+	for _, weighting := range []NormalWeighting{AreaWeighted, AngleWeighted} {
+		normals := mesh.VertexNormals(weighting)
+		for i, tri := range mesh.Triangles {
+			for j, v := range tri.Vertices {
+				// the normals at the corners of a box point away from its center
+				outward := v.Diff(Vec3{0.5, 0.5, 0.5})
+				if normals[i][j].Dot(outward) <= 0 {
+					t.Errorf("Expected outward vertex normal at %v, found: %v", v, normals[i][j])
+				}
+			}
+		}
+	}
+
+	// angle weighting treats every face of the cube corner equally
+	n := mesh.VertexNormals(AngleWeighted)[0][0]
+	if !closeTo(n.X, n.Y, 1e-5) || !closeTo(n.Y, n.Z, 1e-5) {
+		t.Errorf("Expected a symmetric corner normal, found: %v", n)
+	}
+}
+
+func TestInconsistentNormals(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	mesh.Triangles[3].Normal = mesh.Triangles[3].Normal.Scale(-1)
+	mesh.Triangles[7].Normal = Vec3{}
+
+	found := mesh.InconsistentNormals(10)
+	if len(found) != 2 || found[0] != 3 || found[1] != 7 {
+		t.Errorf("Expected triangles [3 7] to be reported, found: %v", found)
+	}
+}
@@ -0,0 +1,307 @@
+package meshful
+
+import (
+	"math"
+	"sort"
+)
+
+// An OBB is an oriented bounding box. The columns of Rotation are the unit directions of the box
+// edges, so Rotation maps a point in the box frame into the mesh frame
+type OBB struct {
+	Center   Vec3
+	Rotation Mat3
+	// full edge lengths of the box along each of its axes
+	Extents Vec3
+}
+
+// Axis returns the unit direction of the i-th box edge
+func (box OBB) Axis(i int) Vec3 {
+	return box.Rotation.Column(i)
+}
+
+// Volume returns the volume enclosed by the box
+func (box OBB) Volume() float32 {
+	return box.Extents.X * box.Extents.Y * box.Extents.Z
+}
+
+// Corners returns the 8 corner points of the box
+func (box OBB) Corners() [8]Vec3 {
+	var corners [8]Vec3
+	half := box.Extents.Scale(0.5)
+	for i := 0; i < 8; i++ {
+		local := Vec3{-half.X, -half.Y, -half.Z}
+		if i&1 != 0 {
+			local.X = half.X
+		}
+		if i&2 != 0 {
+			local.Y = half.Y
+		}
+		if i&4 != 0 {
+			local.Z = half.Z
+		}
+		corners[i] = box.Center.Add(box.Rotation.MulVec(local))
+	}
+	return corners
+}
+
+// OrientedBoundingBox returns an approximation of the minimum-volume box that encloses the mesh.
+// Candidate orientations come from the convex hull face normals, the principal axes of the hull
+// vertices and the coordinate axes. For each candidate one axis is held fixed while the box is
+// fitted in the perpendicular plane with rotating calipers, and the best box found is used as the
+// next candidate until the volume stops improving. ErrEmptyMesh is returned if the mesh has no
+// triangles
+func (mesh *Mesh) OrientedBoundingBox() (OBB, error) {
+	if len(mesh.Triangles) == 0 {
+		return OBB{}, ErrEmptyMesh
+	}
+	points := mesh.uniquePoints()
+
+	// only the hull vertices can touch the box, and a face of the minimum box is often flush
+	// with a hull face. Flat meshes have no hull so all points are used instead
+	h, err := quickhull(points)
+	if err != nil {
+		return fitOBB(points, nil), nil
+	}
+	return fitOBB(h.vertices(), h.normals()), nil
+}
+
+// uniquePoints returns each distinct vertex position in the mesh converted to double precision
+func (mesh *Mesh) uniquePoints() []vec3d {
+	seen := make(map[Vec3]bool)
+	points := []vec3d{}
+	for _, triangle := range mesh.Triangles {
+		for _, v := range triangle.Vertices {
+			if !seen[v] {
+				seen[v] = true
+				points = append(points, toVec3d(v))
+			}
+		}
+	}
+	return points
+}
+
+// obbFit is a candidate box expressed in double precision
+type obbFit struct {
+	axes   [3]vec3d
+	min    vec3d
+	max    vec3d
+	volume float64
+}
+
+// fitOBB searches for the smallest box around points. extraAxes are additional directions that
+// are tried as the fixed axis of a candidate box
+func fitOBB(points []vec3d, extraAxes []vec3d) OBB {
+	best := boxForAxes(points, [3]vec3d{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}})
+
+	candidates := [][3]vec3d{principalFrame(points)}
+	for _, axis := range extraAxes {
+		candidates = append(candidates, frameFromAxis(axis))
+	}
+	candidates = append(candidates, best.axes)
+
+	for _, frame := range candidates {
+		for _, fit := range fitAroundFrame(points, frame) {
+			if fit.volume < best.volume {
+				best = fit
+			}
+		}
+	}
+
+	// refine by refitting around the axes of the best box until the volume stops shrinking
+	for i := 0; i < 10; i++ {
+		improved := false
+		for _, fit := range fitAroundFrame(points, best.axes) {
+			if fit.volume < best.volume*(1-1e-9) {
+				best = fit
+				improved = true
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	return best.obb()
+}
+
+func (fit obbFit) obb() OBB {
+	mid := fit.min.add(fit.max).scale(0.5)
+	center := fit.axes[0].scale(mid.x).add(fit.axes[1].scale(mid.y)).add(fit.axes[2].scale(mid.z))
+	size := fit.max.sub(fit.min)
+	return OBB{
+		Center:   center.vec3(),
+		Rotation: matFromColumns(fit.axes[0], fit.axes[1], fit.axes[2]),
+		Extents:  size.vec3(),
+	}
+}
+
+// fitAroundFrame holds each axis of the frame fixed in turn and returns the minimum-area box
+// found in the plane perpendicular to it
+func fitAroundFrame(points []vec3d, frame [3]vec3d) []obbFit {
+	fits := []obbFit{}
+	for i := 0; i < 3; i++ {
+		fits = append(fits, fitAroundAxis(points, frame[i], frame[(i+1)%3], frame[(i+2)%3]))
+	}
+	return fits
+}
+
+// fitAroundAxis projects the points into the plane perpendicular to axis and uses rotating
+// calipers on their 2D convex hull to find the rectangle with the smallest area
+func fitAroundAxis(points []vec3d, axis, u, v vec3d) obbFit {
+	projected := make([]vec2d, len(points))
+	for i, p := range points {
+		projected[i] = vec2d{p.dot(u), p.dot(v)}
+	}
+	hull := convexHull2D(projected)
+
+	bestArea := math.Inf(1)
+	bestDir := vec2d{1, 0}
+	for i := range hull {
+		edge := hull[(i+1)%len(hull)].sub(hull[i])
+		l := edge.length()
+		if l == 0 {
+			continue
+		}
+		dir := edge.scale(1 / l)
+		perp := vec2d{-dir.y, dir.x}
+
+		minA, maxA := math.Inf(1), math.Inf(-1)
+		minB, maxB := math.Inf(1), math.Inf(-1)
+		for _, p := range hull {
+			a := p.dot(dir)
+			b := p.dot(perp)
+			minA = math.Min(minA, a)
+			maxA = math.Max(maxA, a)
+			minB = math.Min(minB, b)
+			maxB = math.Max(maxB, b)
+		}
+		area := (maxA - minA) * (maxB - minB)
+		if area < bestArea {
+			bestArea = area
+			bestDir = dir
+		}
+	}
+
+	// turn the best 2D direction back into a 3D frame
+	a := u.scale(bestDir.x).add(v.scale(bestDir.y)).normalize()
+	b := axis.cross(a).normalize()
+	return boxForAxes(points, [3]vec3d{a, b, axis.normalize()})
+}
+
+// boxForAxes returns the tightest box around points with edges along the given orthonormal axes
+func boxForAxes(points []vec3d, axes [3]vec3d) obbFit {
+	// keep the frame right-handed
+	if axes[0].cross(axes[1]).dot(axes[2]) < 0 {
+		axes[2] = axes[2].scale(-1)
+	}
+
+	min := vec3d{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max := vec3d{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range points {
+		local := vec3d{p.dot(axes[0]), p.dot(axes[1]), p.dot(axes[2])}
+		min = vec3d{math.Min(min.x, local.x), math.Min(min.y, local.y), math.Min(min.z, local.z)}
+		max = vec3d{math.Max(max.x, local.x), math.Max(max.y, local.y), math.Max(max.z, local.z)}
+	}
+	size := max.sub(min)
+	return obbFit{axes: axes, min: min, max: max, volume: size.x * size.y * size.z}
+}
+
+// principalFrame returns the eigenvectors of the covariance matrix of the points
+func principalFrame(points []vec3d) [3]vec3d {
+	mean := vec3d{}
+	for _, p := range points {
+		mean = mean.add(p)
+	}
+	mean = mean.scale(1 / float64(len(points)))
+
+	var cov [3][3]float64
+	for _, p := range points {
+		d := p.sub(mean)
+		c := [3]float64{d.x, d.y, d.z}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				cov[i][j] += c[i] * c[j]
+			}
+		}
+	}
+
+	_, vectors := symmetricEigen(cov)
+	return vectors
+}
+
+// frameFromAxis builds an arbitrary orthonormal frame whose third axis is the given direction
+func frameFromAxis(axis vec3d) [3]vec3d {
+	w := axis.normalize()
+	helper := vec3d{1, 0, 0}
+	if math.Abs(w.x) > 0.9 {
+		helper = vec3d{0, 1, 0}
+	}
+	u := helper.cross(w).normalize()
+	v := w.cross(u)
+	return [3]vec3d{u, v, w}
+}
+
+// vec2d is a double precision 2D vector
+type vec2d struct {
+	x, y float64
+}
+
+func (v vec2d) add(o vec2d) vec2d {
+	return vec2d{v.x + o.x, v.y + o.y}
+}
+
+func (v vec2d) sub(o vec2d) vec2d {
+	return vec2d{v.x - o.x, v.y - o.y}
+}
+
+func (v vec2d) scale(s float64) vec2d {
+	return vec2d{v.x * s, v.y * s}
+}
+
+func (v vec2d) dot(o vec2d) float64 {
+	return v.x*o.x + v.y*o.y
+}
+
+func (v vec2d) length() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// cross2D returns the z component of the cross product (b - a) x (c - a)
+func cross2D(a, b, c vec2d) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+// convexHull2D returns the convex hull of the points in counter-clockwise order using
+// Andrew's monotone chain algorithm
+func convexHull2D(points []vec2d) []vec2d {
+	sorted := make([]vec2d, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].x != sorted[j].x {
+			return sorted[i].x < sorted[j].x
+		}
+		return sorted[i].y < sorted[j].y
+	})
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	hull := make([]vec2d, 0, 2*len(sorted))
+	// lower hull
+	for _, p := range sorted {
+		for len(hull) >= 2 && cross2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// upper hull
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && cross2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,52 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrientedBoundingBoxOfRotatedBox(t *testing.T) {
+	box := makeBoxMesh(Vec3{0, 0, 0}, Vec3{4, 2, 1})
+
+	// rotate 30 degrees around z and then 20 degrees around x
+	cz, sz := float32(math.Cos(math.Pi/6)), float32(math.Sin(math.Pi/6))
+	cx, sx := float32(math.Cos(math.Pi/9)), float32(math.Sin(math.Pi/9))
+	rz := Mat3{{cz, -sz, 0}, {sz, cz, 0}, {0, 0, 1}}
+	rx := Mat3{{1, 0, 0}, {0, cx, -sx}, {0, sx, cx}}
+	mesh := rotateMesh(rotateMesh(box, rz), rx)
+
+	obb, err := mesh.OrientedBoundingBox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(float64(obb.Volume())-8) > 1e-3 {
+		t.Errorf("Expected oriented box volume of 8, found: %v", obb.Volume())
+	}
+
+	aabb, _ := mesh.BoundingBox()
+	size := aabb.Size()
+	if obb.Volume() >= size.X*size.Y*size.Z {
+		t.Errorf("Expected oriented box to be smaller than the axis-aligned box")
+	}
+
+	// every vertex should be inside the box
+	inverse := obb.Rotation.Transpose()
+	for _, tri := range mesh.Triangles {
+		for _, v := range tri.Vertices {
+			local := inverse.MulVec(v.Diff(obb.Center))
+			if math.Abs(float64(local.X)) > float64(obb.Extents.X)/2+1e-4 ||
+				math.Abs(float64(local.Y)) > float64(obb.Extents.Y)/2+1e-4 ||
+				math.Abs(float64(local.Z)) > float64(obb.Extents.Z)/2+1e-4 {
+				t.Errorf("Vertex %v is outside of the oriented box", v)
+			}
+		}
+	}
+}
+
+func TestOrientedBoundingBoxEmptyMesh(t *testing.T) {
+	mesh := &Mesh{}
+	_, err := mesh.OrientedBoundingBox()
+	if err != ErrEmptyMesh {
+		t.Errorf("Expected ErrEmptyMesh, found: %v", err)
+	}
+}
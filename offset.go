@@ -0,0 +1,252 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidThickness is used when a wall thickness is not positive
+var ErrInvalidThickness = errors.New("Wall thickness must be positive")
+
+// ErrTooThin is used when hollowing leaves no room for a cavity because every part of the mesh is
+// thinner than twice the wall thickness
+var ErrTooThin = errors.New("Mesh is too thin to hollow")
+
+// OffsetOptions controls the grid offset surfaces are extracted from
+type OffsetOptions struct {
+	// size of the grid cells. Smaller cells follow the shape more closely but take longer and give
+	// more triangles. 0 uses 1/64 of the longest side of the mesh
+	CellSize float32
+}
+
+// Offset returns a new surface at a constant distance from the closed mesh, outside it for a
+// positive distance and inside it for a negative one. The signed distance to the mesh is sampled
+// on a grid and the surface is extracted where it equals the distance, so the result is always
+// closed and sharp corners of outward offsets are rounded. Only points near the new surface are
+// measured exactly, the rest of the grid only needs the side of the surface it is on. An inward
+// offset that removes the whole mesh returns a mesh without triangles
+func (mesh *Mesh) Offset(distance float32, opts OffsetOptions) (*Mesh, error) {
+	if len(mesh.Triangles) == 0 {
+		return nil, ErrEmptyMesh
+	}
+	if !mesh.isClosed() {
+		return nil, ErrOpenMesh
+	}
+	box, _ := mesh.BoundingBox()
+	size := box.Size()
+	cell := opts.CellSize
+	if cell <= 0 {
+		cell = max32(size.X, max32(size.Y, size.Z)) / 64
+	}
+
+	// the grid reaches past the offset surface so the surface never touches its sides
+	g := newDistanceGrid(box.Pad(max32(distance, 0)+2*cell), float64(cell))
+	bvh := NewBVH(mesh)
+	g.fill(func(p vec3d) float64 {
+		d, _ := bvh.SignedDistance(p.vec3())
+		return float64(d) - float64(distance)
+	}, [3]int{}, [3]int{g.size[0] - 1, g.size[1] - 1, g.size[2] - 1})
+	return g.extract().toMesh(), nil
+}
+
+// Hollow returns a copy of the closed mesh with a cavity inside it so that every wall is thickness
+// thick, which saves material for resin prints. The cavity is the inward offset of the surface
+// turned inside out, so Volume measures only the material that is left
+func (mesh *Mesh) Hollow(thickness float32, opts OffsetOptions) (*Mesh, error) {
+	if thickness <= 0 {
+		return nil, ErrInvalidThickness
+	}
+	inner, err := mesh.Offset(-thickness, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(inner.Triangles) == 0 {
+		return nil, ErrTooThin
+	}
+
+	hollow := &Mesh{Triangles: append([]Triangle{}, mesh.Triangles...)}
+	for _, t := range inner.Triangles {
+		t.Vertices[1], t.Vertices[2] = t.Vertices[2], t.Vertices[1]
+		t.Normal = t.Normal.Scale(-1)
+		hollow.Triangles = append(hollow.Triangles, t)
+	}
+	return hollow, nil
+}
+
+// distanceGrid holds samples of a function on a regular grid of points. The surface is where the
+// function crosses zero, with negative values inside
+type distanceGrid struct {
+	origin vec3d
+	cell   float64
+	// number of points along each axis
+	size   [3]int
+	values []float64
+}
+
+func newDistanceGrid(box AABB, cell float64) *distanceGrid {
+	g := &distanceGrid{origin: toVec3d(box.Min), cell: cell}
+	extent := toVec3d(box.Size())
+	for axis, e := range []float64{extent.x, extent.y, extent.z} {
+		g.size[axis] = int(math.Ceil(e/cell)) + 1
+	}
+	g.values = make([]float64, g.size[0]*g.size[1]*g.size[2])
+	return g
+}
+
+func (g *distanceGrid) index(i, j, k int) int {
+	return (k*g.size[1]+j)*g.size[0] + i
+}
+
+func (g *distanceGrid) point(i, j, k int) vec3d {
+	return g.origin.add(vec3d{float64(i), float64(j), float64(k)}.scale(g.cell))
+}
+
+// position returns the point of the grid with the index
+func (g *distanceGrid) position(index int) vec3d {
+	i := index % g.size[0]
+	j := index / g.size[0] % g.size[1]
+	k := index / (g.size[0] * g.size[1])
+	return g.point(i, j, k)
+}
+
+// fill samples f at the grid points from lo to hi inclusive. A distance changes no faster than the
+// point it is measured from moves, so when the center of the block is far enough from the surface
+// every point in it is on the same side and no cell next to it is crossed by the surface. Those
+// points get the value at the center, and only blocks near the surface are split further
+func (g *distanceGrid) fill(f func(vec3d) float64, lo, hi [3]int) {
+	center := g.point(lo[0], lo[1], lo[2]).add(g.point(hi[0], hi[1], hi[2])).scale(0.5)
+	value := f(center)
+	if lo == hi {
+		g.values[g.index(lo[0], lo[1], lo[2])] = value
+		return
+	}
+	radius := g.point(hi[0], hi[1], hi[2]).sub(g.point(lo[0], lo[1], lo[2])).length() / 2
+	if math.Abs(value) > radius+2*g.cell {
+		for k := lo[2]; k <= hi[2]; k++ {
+			for j := lo[1]; j <= hi[1]; j++ {
+				for i := lo[0]; i <= hi[0]; i++ {
+					g.values[g.index(i, j, k)] = value
+				}
+			}
+		}
+		return
+	}
+
+	axis := 0
+	for a := 1; a < 3; a++ {
+		if hi[a]-lo[a] > hi[axis]-lo[axis] {
+			axis = a
+		}
+	}
+	mid := (lo[axis] + hi[axis]) / 2
+	first, second := hi, lo
+	first[axis], second[axis] = mid, mid+1
+	g.fill(f, lo, first)
+	g.fill(f, second, hi)
+}
+
+// kuhnTetrahedra splits a grid cell into six tetrahedra around its diagonal from corner 0 to
+// corner 7, where bit 0, 1 and 2 of a corner step along x, y and z. Every cell splits its faces the
+// same way, so the tetrahedra of neighboring cells meet along whole faces
+var kuhnTetrahedra = [6][4]int{
+	{0, 1, 3, 7},
+	{0, 1, 5, 7},
+	{0, 2, 3, 7},
+	{0, 2, 6, 7},
+	{0, 4, 5, 7},
+	{0, 4, 6, 7},
+}
+
+// extract returns the surface where the values cross zero using marching tetrahedra. The vertices
+// are shared through the grid edges they are on, so the surface is closed wherever it does not
+// reach the sides of the grid, and the triangles face the positive side
+func (g *distanceGrid) extract() *indexedMesh {
+	im := &indexedMesh{}
+	vertices := make(map[[2]int]int)
+	// crossing returns the vertex where the surface crosses the edge between two grid points
+	crossing := func(a, b int) int {
+		if a > b {
+			a, b = b, a
+		}
+		key := [2]int{a, b}
+		if v, ok := vertices[key]; ok {
+			return v
+		}
+		t := g.values[a] / (g.values[a] - g.values[b])
+		// crossings stay off the grid points so the crossings on different edges never meet
+		t = math.Max(1e-3, math.Min(1-1e-3, t))
+		p := g.position(a).add(g.position(b).sub(g.position(a)).scale(t))
+		vertices[key] = len(im.vertices)
+		im.vertices = append(im.vertices, p.vec3())
+		return vertices[key]
+	}
+	add := func(flip bool, faces ...[3]int) {
+		for _, face := range faces {
+			if flip {
+				face[1], face[2] = face[2], face[1]
+			}
+			im.faces = append(im.faces, face)
+		}
+	}
+
+	for k := 0; k+1 < g.size[2]; k++ {
+		for j := 0; j+1 < g.size[1]; j++ {
+			for i := 0; i+1 < g.size[0]; i++ {
+				index := func(c int) int {
+					return g.index(i+c&1, j+c>>1&1, k+c>>2)
+				}
+				for _, tet := range kuhnTetrahedra {
+					var inside, outside []int
+					for _, c := range tet {
+						if g.values[index(c)] < 0 {
+							inside = append(inside, c)
+						} else {
+							outside = append(outside, c)
+						}
+					}
+
+					// the orientation is worked out from the corners, which are never degenerate,
+					// instead of from the crossings
+					switch len(inside) {
+					case 1:
+						a, b, c, d := inside[0], outside[0], outside[1], outside[2]
+						add(orientCorners(a, b, c, d) < 0,
+							[3]int{crossing(index(a), index(b)), crossing(index(a), index(c)), crossing(index(a), index(d))})
+					case 3:
+						a, b, c, d := inside[0], inside[1], inside[2], outside[0]
+						add(orientCorners(a, b, c, d) < 0,
+							[3]int{crossing(index(a), index(d)), crossing(index(b), index(d)), crossing(index(c), index(d))})
+					case 2:
+						a, b, c, d := inside[0], inside[1], outside[0], outside[1]
+						ac, ad := crossing(index(a), index(c)), crossing(index(a), index(d))
+						bc, bd := crossing(index(b), index(c)), crossing(index(b), index(d))
+						// the quad faces from a and b towards c and d when (d - c) x (b - a) does
+						normal := cornerCross(cornerSub(d, c), cornerSub(b, a))
+						u, v := cornerSub(c, a), cornerSub(d, b)
+						towards := [3]int{u[0] + v[0], u[1] + v[1], u[2] + v[2]}
+						add(normal[0]*towards[0]+normal[1]*towards[1]+normal[2]*towards[2] < 0,
+							[3]int{ac, ad, bd}, [3]int{ac, bd, bc})
+					}
+				}
+			}
+		}
+	}
+	return im
+}
+
+// cornerSub returns the offset from corner b to corner a of a grid cell
+func cornerSub(a, b int) [3]int {
+	return [3]int{a&1 - b&1, a>>1&1 - b>>1&1, a>>2 - b>>2}
+}
+
+func cornerCross(u, v [3]int) [3]int {
+	return [3]int{u[1]*v[2] - u[2]*v[1], u[2]*v[0] - u[0]*v[2], u[0]*v[1] - u[1]*v[0]}
+}
+
+// orientCorners returns the sign of the volume of the tetrahedron between four corners of a grid
+// cell. It is positive when d is on the side of triangle abc its counterclockwise normal points to
+func orientCorners(a, b, c, d int) int {
+	n := cornerCross(cornerSub(b, a), cornerSub(c, a))
+	u := cornerSub(d, a)
+	return n[0]*u[0] + n[1]*u[1] + n[2]*u[2]
+}
@@ -0,0 +1,88 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOffsetBox(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{-1, -1, -1}, Vec3{1, 1, 1})
+
+	// growing a box adds slabs on the faces, quarter cylinders on the edges and spheres at corners
+	r := 0.2
+	grown, err := mesh.Offset(float32(r), OffsetOptions{CellSize: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, grown)
+	expected := 8 + 24*r + 6*math.Pi*r*r + 4*math.Pi*r*r*r/3
+	if v := float64(grown.Volume()); math.Abs(v-expected) > 0.02*expected {
+		t.Errorf("Expected volume %v, found: %v", expected, v)
+	}
+	box, _ := grown.BoundingBox()
+	if !closeTo(box.Max.X, 1.2, 0.01) || !closeTo(box.Min.Z, -1.2, 0.01) {
+		t.Errorf("Expected the box to grow by 0.2, found: %v", box)
+	}
+
+	shrunk, err := mesh.Offset(-0.2, OffsetOptions{CellSize: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, shrunk)
+	if v := shrunk.Volume(); !closeTo(v, 1.6*1.6*1.6, 0.05) {
+		t.Errorf("Expected a box of volume 4.096, found: %v", v)
+	}
+
+	gone, err := mesh.Offset(-1.5, OffsetOptions{})
+	if err != nil || len(gone.Triangles) != 0 {
+		t.Errorf("Expected nothing to be left, found %d triangles and error %v", len(gone.Triangles), err)
+	}
+}
+
+func TestOffsetSphere(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 32, 64)
+	shrunk, err := mesh.Offset(-0.3, OffsetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tri := range shrunk.Triangles {
+		for _, v := range tri.Vertices {
+			if r := toVec3d(v).length(); math.Abs(r-0.7) > 0.01 {
+				t.Fatalf("Expected vertices at radius 0.7, found: %v", r)
+			}
+		}
+	}
+
+	open := &Mesh{Triangles: mesh.Triangles[1:]}
+	if _, err := open.Offset(0.1, OffsetOptions{}); err != ErrOpenMesh {
+		t.Errorf("Expected ErrOpenMesh, found: %v", err)
+	}
+}
+
+func TestHollow(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{4, 4, 2})
+	hollow, err := mesh.Hollow(0.25, OffsetOptions{CellSize: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClosed(t, hollow)
+	if len(hollow.Shells(EdgeConnectivity)) != 2 {
+		t.Fatalf("Expected an outer shell and a cavity")
+	}
+	// the outer surface is kept as it is
+	for i := range mesh.Triangles {
+		if hollow.Triangles[i] != mesh.Triangles[i] {
+			t.Fatalf("Expected the outside to be unchanged")
+		}
+	}
+	if v := hollow.Volume(); !closeTo(v, 32-3.5*3.5*1.5, 0.2) {
+		t.Errorf("Expected the volume of the walls, found: %v", v)
+	}
+
+	if _, err := mesh.Hollow(1.2, OffsetOptions{}); err != ErrTooThin {
+		t.Errorf("Expected ErrTooThin, found: %v", err)
+	}
+	if _, err := mesh.Hollow(0, OffsetOptions{}); err != ErrInvalidThickness {
+		t.Errorf("Expected ErrInvalidThickness, found: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package meshful
+
+// Flip reverses the winding order of the triangle and negates its normal
+func (t *Triangle) Flip() {
+	t.Vertices[1], t.Vertices[2] = t.Vertices[2], t.Vertices[1]
+	t.Normal = t.Normal.Scale(-1)
+}
+
+// FixOrientation makes the winding of every connected component of the mesh consistent and
+// outward facing. The winding of the first triangle in a component is propagated to its
+// neighbors across each edge shared by exactly two triangles, then the whole component is
+// flipped if its signed volume is negative. Normals of flipped triangles are negated. The number
+// of triangles that were flipped is returned
+func (mesh *Mesh) FixOrientation() int {
+	im := newIndexedMesh(mesh)
+	edges := im.edgeFaces()
+	flip := im.orientationFlips(edges)
+
+	flipped := 0
+	for i, f := range flip {
+		if f {
+			mesh.Triangles[i].Flip()
+			flipped++
+		}
+	}
+	return flipped
+}
+
+// orientationFlips returns for each face whether it has to be flipped to make its component
+// consistently wound and outward facing
+func (im *indexedMesh) orientationFlips(edges map[[2]int][]int) []bool {
+	flip := make([]bool, len(im.faces))
+	visited := make([]bool, len(im.faces))
+
+	for start := range im.faces {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		component := []int{start}
+
+		// breadth first walk across manifold edges
+		for next := 0; next < len(component); next++ {
+			current := component[next]
+			face := im.faces[current]
+			for j := 0; j < 3; j++ {
+				a, b := face[j], face[(j+1)%3]
+				if flip[current] {
+					a, b = b, a
+				}
+				shared := edges[edgeKey(a, b)]
+				if len(shared) != 2 {
+					continue
+				}
+				neighbor := shared[0]
+				if neighbor == current {
+					neighbor = shared[1]
+				}
+				if visited[neighbor] {
+					continue
+				}
+				visited[neighbor] = true
+				// consistent neighbors traverse the shared edge in the opposite direction
+				flip[neighbor] = hasDirectedEdge(im.faces[neighbor], a, b)
+				component = append(component, neighbor)
+			}
+		}
+
+		// flip the whole component if it is inside out
+		var volume float64
+		for _, i := range component {
+			t := Triangle{Vertices: [3]Vec3{im.vertices[im.faces[i][0]], im.vertices[im.faces[i][1]], im.vertices[im.faces[i][2]]}}
+			v := float64(t.SignedVolume())
+			if flip[i] {
+				v = -v
+			}
+			volume += v
+		}
+		if volume < 0 {
+			for _, i := range component {
+				flip[i] = !flip[i]
+			}
+		}
+	}
+	return flip
+}
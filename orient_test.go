@@ -0,0 +1,41 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestFixOrientationOfPartiallyFlippedMesh(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	mesh.Triangles[0].Flip()
+	mesh.Triangles[5].Flip()
+	mesh.Triangles[6].Flip()
+
+	flipped := mesh.FixOrientation()
+	if flipped != 3 {
+		t.Errorf("Expected 3 triangles to be flipped, found: %d", flipped)
+	}
+	checkClosed(t, mesh)
+	if !closeTo(mesh.Volume(), 1, 1e-5) {
+		t.Errorf("Expected volume of 1, found: %v", mesh.Volume())
+	}
+}
+
+func TestFixOrientationOfInsideOutMesh(t *testing.T) {
+	inverted := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	for i := range inverted.Triangles {
+		inverted.Triangles[i].Flip()
+	}
+	correct := makeBoxMesh(Vec3{2, 0, 0}, Vec3{3, 1, 1})
+
+	mesh := &Mesh{Triangles: append(inverted.Triangles, correct.Triangles...)}
+	flipped := mesh.FixOrientation()
+	if flipped != 12 {
+		t.Errorf("Expected only the inverted box to be flipped, found %d flips", flipped)
+	}
+	if !closeTo(mesh.Volume(), 2, 1e-5) {
+		t.Errorf("Expected volume of 2, found: %v", mesh.Volume())
+	}
+	if len(mesh.InconsistentNormals(1)) != 0 {
+		t.Errorf("Expected normals to be flipped along with the triangles")
+	}
+}
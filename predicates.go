@@ -0,0 +1,75 @@
+package meshful
+
+import (
+	"math"
+	"math/big"
+)
+
+// The orientation predicates first evaluate the determinant in float64 and only fall back to
+// exact arithmetic when the result is too close to zero to trust its sign. Coordinates are
+// float64 so every difference and product is exact at the precision used by the fallback
+
+const exactPrecision = 1024
+
+// errorBound is a conservative bound on the relative rounding error of the float64 determinants
+const errorBound = 1e-12
+
+// orient3d returns 1 if d lies above the plane through a, b and c (where counter-clockwise when
+// seen from above), -1 if it lies below and 0 if the four points are coplanar
+func orient3d(a, b, c, d vec3d) int {
+	ax, ay, az := a.x-d.x, a.y-d.y, a.z-d.z
+	bx, by, bz := b.x-d.x, b.y-d.y, b.z-d.z
+	cx, cy, cz := c.x-d.x, c.y-d.y, c.z-d.z
+
+	det := ax*(by*cz-bz*cy) + ay*(bz*cx-bx*cz) + az*(bx*cy-by*cx)
+	permanent := math.Abs(ax)*(math.Abs(by*cz)+math.Abs(bz*cy)) +
+		math.Abs(ay)*(math.Abs(bz*cx)+math.Abs(bx*cz)) +
+		math.Abs(az)*(math.Abs(bx*cy)+math.Abs(by*cx))
+	if det > errorBound*permanent {
+		return -1
+	}
+	if -det > errorBound*permanent {
+		return 1
+	}
+	return -orient3dExact(a, b, c, d)
+}
+
+func orient3dExact(a, b, c, d vec3d) int {
+	sub := func(p, q float64) *big.Float {
+		return new(big.Float).SetPrec(exactPrecision).Sub(big.NewFloat(p), big.NewFloat(q))
+	}
+	mul := func(p, q *big.Float) *big.Float {
+		return new(big.Float).SetPrec(exactPrecision).Mul(p, q)
+	}
+	ax, ay, az := sub(a.x, d.x), sub(a.y, d.y), sub(a.z, d.z)
+	bx, by, bz := sub(b.x, d.x), sub(b.y, d.y), sub(b.z, d.z)
+	cx, cy, cz := sub(c.x, d.x), sub(c.y, d.y), sub(c.z, d.z)
+
+	m1 := new(big.Float).SetPrec(exactPrecision).Sub(mul(by, cz), mul(bz, cy))
+	m2 := new(big.Float).SetPrec(exactPrecision).Sub(mul(bz, cx), mul(bx, cz))
+	m3 := new(big.Float).SetPrec(exactPrecision).Sub(mul(bx, cy), mul(by, cx))
+	det := new(big.Float).SetPrec(exactPrecision).Add(mul(ax, m1), mul(ay, m2))
+	det.Add(det, mul(az, m3))
+	return det.Sign()
+}
+
+// orient2d returns 1 if a, b and c wind counter-clockwise, -1 if they wind clockwise and 0 if they
+// are collinear
+func orient2d(a, b, c vec2d) int {
+	left := (a.x - c.x) * (b.y - c.y)
+	right := (a.y - c.y) * (b.x - c.x)
+	det := left - right
+	if det > errorBound*(math.Abs(left)+math.Abs(right)) {
+		return 1
+	}
+	if -det > errorBound*(math.Abs(left)+math.Abs(right)) {
+		return -1
+	}
+
+	sub := func(p, q float64) *big.Float {
+		return new(big.Float).SetPrec(exactPrecision).Sub(big.NewFloat(p), big.NewFloat(q))
+	}
+	l := new(big.Float).SetPrec(exactPrecision).Mul(sub(a.x, c.x), sub(b.y, c.y))
+	r := new(big.Float).SetPrec(exactPrecision).Mul(sub(a.y, c.y), sub(b.x, c.x))
+	return l.Sub(l, r).Sign()
+}
@@ -0,0 +1,115 @@
+package meshful
+
+import (
+	"math"
+	"sort"
+)
+
+// A RayHit describes where a ray crosses a triangle of a mesh
+type RayHit struct {
+	// distance from the ray origin to the hit
+	Distance float32
+
+	// index of the triangle that was hit
+	Triangle int
+
+	// weights of the triangle's 3 vertices at the hit point, they add up to 1
+	Barycentric Vec3
+
+	// position of the hit on the triangle
+	Point Vec3
+}
+
+// Raycast returns the closest hit of a ray starting at origin and travelling along dir. The
+// direction does not need to be normalized. The second return value is false if the ray misses
+// the mesh. A BVH is built for every call, so build one with NewBVH and use BVH.Raycast when
+// casting many rays against the same mesh
+func (mesh *Mesh) Raycast(origin, dir Vec3) (RayHit, bool) {
+	return NewBVH(mesh).Raycast(origin, dir)
+}
+
+// RaycastAll returns every hit of a ray along its path sorted by distance. See Raycast
+func (mesh *Mesh) RaycastAll(origin, dir Vec3) []RayHit {
+	return NewBVH(mesh).RaycastAll(origin, dir)
+}
+
+// Raycast returns the closest hit of a ray starting at origin and travelling along dir. The
+// direction does not need to be normalized. The second return value is false if the ray misses
+// the mesh
+func (bvh *BVH) Raycast(origin, dir Vec3) (RayHit, bool) {
+	var best RayHit
+	found := false
+	bvh.traverseRay(origin, dir, func(hit RayHit) float64 {
+		if !found || hit.Distance < best.Distance {
+			best = hit
+			found = true
+		}
+		return float64(best.Distance)
+	})
+	return best, found
+}
+
+// IntersectRay returns the closest triangle hit by the ray from origin along dir and the distance
+// to the hit measured in multiples of dir. The last return value is false if nothing was hit
+func (bvh *BVH) IntersectRay(origin, dir Vec3) (int, float32, bool) {
+	hit, ok := bvh.Raycast(origin, dir)
+	if !ok {
+		return -1, 0, false
+	}
+	return hit.Triangle, float32(float64(hit.Distance) / toVec3d(dir).length()), true
+}
+
+// RaycastAll returns every hit of a ray along its path sorted by distance. A ray that passes
+// exactly through an edge or vertex is reported once for each triangle it touches there
+func (bvh *BVH) RaycastAll(origin, dir Vec3) []RayHit {
+	hits := []RayHit{}
+	bvh.traverseRay(origin, dir, func(hit RayHit) float64 {
+		hits = append(hits, hit)
+		return math.Inf(1)
+	})
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Distance != hits[j].Distance {
+			return hits[i].Distance < hits[j].Distance
+		}
+		return hits[i].Triangle < hits[j].Triangle
+	})
+	return hits
+}
+
+// traverseRay calls visit for every triangle the ray hits. visit returns the distance beyond
+// which further hits are no longer of interest so that subtrees can be skipped
+func (bvh *BVH) traverseRay(origin, dir Vec3, visit func(RayHit) float64) {
+	d := toVec3d(dir).normalize()
+	if len(bvh.nodes) == 0 || d.length() == 0 {
+		return
+	}
+	o := toVec3d(origin)
+	inv := vec3d{1 / d.x, 1 / d.y, 1 / d.z}
+
+	limit := math.Inf(1)
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &bvh.nodes[n]
+		if t, ok := rayBox(o, inv, node.box); !ok || t > limit {
+			continue
+		}
+		if node.count > 0 {
+			for _, i := range bvh.indices[node.offset : node.offset+node.count] {
+				dist, u, v, ok := rayTriangle(o, d, &bvh.mesh.Triangles[i])
+				if !ok || dist > limit {
+					continue
+				}
+				limit = visit(RayHit{
+					Distance:    float32(dist),
+					Triangle:    i,
+					Barycentric: Vec3{float32(1 - u - v), float32(u), float32(v)},
+					Point:       o.add(d.scale(dist)).vec3(),
+				})
+			}
+			continue
+		}
+		stack = append(stack, node.offset, n+1)
+	}
+}
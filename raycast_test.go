@@ -0,0 +1,52 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestRaycast(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+
+	hit, ok := mesh.Raycast(Vec3{0.5, 0.5, 5}, Vec3{0, 0, -2})
+	if !ok {
+		t.Fatal("Expected the ray to hit the box")
+	}
+	if !closeTo(hit.Distance, 3, 1e-6) || hit.Point != (Vec3{0.5, 0.5, 2}) {
+		t.Errorf("Expected a hit on the top at distance 3, found: %+v", hit)
+	}
+	if hit.Triangle != 2 && hit.Triangle != 3 {
+		t.Errorf("Expected to hit a top triangle, found: %d", hit.Triangle)
+	}
+
+	// the barycentric weights rebuild the hit point
+	tri := mesh.Triangles[hit.Triangle]
+	b := hit.Barycentric
+	p := tri.Vertices[0].Scale(b.X).Add(tri.Vertices[1].Scale(b.Y)).Add(tri.Vertices[2].Scale(b.Z))
+	if !closeTo(p.X, 0.5, 1e-6) || !closeTo(p.Y, 0.5, 1e-6) || !closeTo(p.Z, 2, 1e-6) {
+		t.Errorf("Expected barycentric weights to give the hit point, found: %v", p)
+	}
+
+	if _, ok := mesh.Raycast(Vec3{0.5, 0.5, 5}, Vec3{0, 0, 1}); ok {
+		t.Errorf("Expected a ray pointing away to miss")
+	}
+}
+
+func TestRaycastAllMeasuresWallThickness(t *testing.T) {
+	outer := makeBoxMesh(Vec3{0, 0, 0}, Vec3{10, 10, 10})
+	inner := makeBoxMesh(Vec3{2, 2, 2}, Vec3{8, 8, 8})
+	mesh := Merge(outer, inner)
+
+	hits := mesh.RaycastAll(Vec3{5.5, 4.5, -1}, Vec3{0, 0, 1})
+	if len(hits) != 4 {
+		t.Fatalf("Expected 4 hits through a hollow box, found: %d", len(hits))
+	}
+	expected := []float32{1, 3, 9, 11}
+	for i, hit := range hits {
+		if !closeTo(hit.Distance, expected[i], 1e-5) {
+			t.Errorf("Expected hit %d at distance %v, found: %v", i, expected[i], hit.Distance)
+		}
+	}
+	if wall := hits[1].Distance - hits[0].Distance; !closeTo(wall, 2, 1e-5) {
+		t.Errorf("Expected a wall thickness of 2, found: %v", wall)
+	}
+}
@@ -0,0 +1,307 @@
+package meshful
+
+import (
+	"math"
+)
+
+// RemeshOptions controls the triangles Remesh produces
+type RemeshOptions struct {
+	// length the edges of the new mesh should have. 0 uses the average edge length of the mesh
+	TargetEdgeLength float32
+
+	// number of rounds of splits, collapses, flips and relaxation. 0 uses 10
+	Iterations int
+
+	// edges where the faces meet at more than this angle in degrees are kept as sharp features.
+	// 0 only keeps boundary edges
+	FeatureAngle float32
+}
+
+// Remesh returns a copy of the mesh rebuilt from nearly equilateral triangles whose edges are all
+// close to the target length (Botsch and Kobbelt, "A Remeshing Approach to Multiresolution
+// Modeling"). Each iteration splits long edges, collapses short ones, flips edges so vertices have
+// six neighbors and spreads the vertices evenly over the surface, projecting them back onto the
+// original mesh. Boundary and feature edges are only split or collapsed along themselves and their
+// vertices are not relaxed, so they keep the spacing splits and collapses leave. The corners where
+// they meet never move
+func (mesh *Mesh) Remesh(opts RemeshOptions) *Mesh {
+	im := newIndexedMesh(mesh)
+	r := &remesher{
+		tm:       newTrimesh(im),
+		features: make(map[[2]int]bool),
+		bvh:      NewBVH(mesh),
+	}
+	tm := r.tm
+
+	edges := im.edgeFaces()
+	cosine := math.Cos(float64(opts.FeatureAngle) * math.Pi / 180)
+	var total float64
+	for key, faces := range edges {
+		total += tm.positions[key[0]].sub(tm.positions[key[1]]).length()
+		if len(faces) != 2 {
+			r.features[key] = true
+		} else if opts.FeatureAngle > 0 {
+			a := tm.faceNormal(faces[0], nil, vec3d{}).normalize()
+			b := tm.faceNormal(faces[1], nil, vec3d{}).normalize()
+			if a.dot(b) < cosine {
+				r.features[key] = true
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return &Mesh{}
+	}
+
+	r.length = float64(opts.TargetEdgeLength)
+	if r.length <= 0 {
+		r.length = total / float64(len(edges))
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = 10
+	}
+
+	// vertices on features and corners where features meet or turn
+	r.featureVertex = make([]bool, len(tm.positions))
+	r.corner = make([]bool, len(tm.positions))
+	featureEdges := make(map[int][]int)
+	for key := range r.features {
+		featureEdges[key[0]] = append(featureEdges[key[0]], key[1])
+		featureEdges[key[1]] = append(featureEdges[key[1]], key[0])
+	}
+	for v, ends := range featureEdges {
+		r.featureVertex[v] = true
+		if len(ends) != 2 {
+			r.corner[v] = true
+			continue
+		}
+		in := tm.positions[v].sub(tm.positions[ends[0]]).normalize()
+		out := tm.positions[ends[1]].sub(tm.positions[v]).normalize()
+		if opts.FeatureAngle > 0 && in.dot(out) < cosine {
+			r.corner[v] = true
+		}
+	}
+
+	for i := 0; i < iterations; i++ {
+		r.splitLongEdges()
+		r.collapseShortEdges()
+		r.equalizeValences()
+		r.relax()
+	}
+	return tm.toIndexed().toMesh()
+}
+
+type remesher struct {
+	tm       *trimesh
+	length   float64
+	features map[[2]int]bool
+	// vertices on feature edges, which only move when an edge along the feature collapses
+	featureVertex []bool
+	// vertices that never move
+	corner []bool
+	// the original surface the vertices are projected back onto
+	bvh *BVH
+}
+
+// edges returns every edge of the living faces once
+func (r *remesher) edges() [][2]int {
+	seen := make(map[[2]int]bool)
+	edges := [][2]int{}
+	for f, face := range r.tm.faces {
+		if !r.tm.alive[f] {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			key := edgeKey(face[k], face[(k+1)%3])
+			if !seen[key] {
+				seen[key] = true
+				edges = append(edges, key)
+			}
+		}
+	}
+	return edges
+}
+
+func (r *remesher) edgeLength(u, v int) float64 {
+	return r.tm.positions[u].sub(r.tm.positions[v]).length()
+}
+
+// splitLongEdges splits edges longer than 4/3 of the target at their middle until none are left
+func (r *remesher) splitLongEdges() {
+	tm := r.tm
+	for pass := 0; pass < 32; pass++ {
+		split := false
+		for _, e := range r.edges() {
+			u, v := e[0], e[1]
+			if r.edgeLength(u, v) <= 4.0/3*r.length {
+				continue
+			}
+			m := tm.splitEdge(u, v, tm.positions[u].add(tm.positions[v]).scale(0.5))
+			r.corner = append(r.corner, false)
+			r.featureVertex = append(r.featureVertex, r.features[e])
+			if r.features[e] {
+				delete(r.features, e)
+				r.features[edgeKey(u, m)] = true
+				r.features[edgeKey(m, v)] = true
+			}
+			split = true
+		}
+		if !split {
+			return
+		}
+	}
+}
+
+// collapseShortEdges collapses edges shorter than 4/5 of the target as long as no edge around the
+// merged vertex gets longer than 4/3 of the target
+func (r *remesher) collapseShortEdges() {
+	tm := r.tm
+	for _, e := range r.edges() {
+		u, v := e[0], e[1]
+		if len(tm.facesOf(u)) == 0 || len(tm.facesOf(v)) == 0 || r.edgeLength(u, v) >= 4.0/5*r.length {
+			continue
+		}
+
+		// keep the vertex that is more constrained and move the other one onto it
+		rank := func(w int) int {
+			if r.corner[w] {
+				return 2
+			}
+			if r.featureVertex[w] {
+				return 1
+			}
+			return 0
+		}
+		if rank(v) > rank(u) {
+			u, v = v, u
+		}
+		var p vec3d
+		switch {
+		case r.corner[v]:
+			// both ends are corners
+			continue
+		case r.featureVertex[v]:
+			// two feature vertices may only merge along the feature between them
+			if !r.features[e] {
+				continue
+			}
+			p = tm.positions[u]
+		case r.featureVertex[u]:
+			p = tm.positions[u]
+		default:
+			p = tm.positions[u].add(tm.positions[v]).scale(0.5)
+		}
+
+		tooLong := false
+		for _, w := range tm.neighbors(v) {
+			if p.sub(tm.positions[w]).length() > 4.0/3*r.length {
+				tooLong = true
+			}
+		}
+		if tooLong || !tm.canCollapse(u, v) || tm.flipsFaces(u, v, p) {
+			continue
+		}
+
+		for _, w := range tm.neighbors(v) {
+			if key := edgeKey(v, w); r.features[key] {
+				delete(r.features, key)
+				if w != u {
+					r.features[edgeKey(u, w)] = true
+				}
+			}
+		}
+		tm.collapse(u, v, p)
+	}
+}
+
+// equalizeValences flips edges when that brings the vertices around them closer to having six
+// neighbors, or four on a boundary
+func (r *remesher) equalizeValences() {
+	tm := r.tm
+	valence := func(v int) int {
+		return len(tm.neighbors(v))
+	}
+	target := func(v int) int {
+		// creases inside the mesh are surrounded by faces like any other vertex
+		for _, w := range tm.neighbors(v) {
+			if len(tm.edgeFaces(v, w)) == 1 {
+				return 4
+			}
+		}
+		return 6
+	}
+	for _, e := range r.edges() {
+		u, v := e[0], e[1]
+		if r.features[e] {
+			continue
+		}
+		faces := tm.edgeFaces(u, v)
+		if len(faces) != 2 {
+			continue
+		}
+		a := tm.oppositeCorner(faces[0], u, v)
+		b := tm.oppositeCorner(faces[1], u, v)
+		if a == b || len(tm.edgeFaces(a, b)) > 0 {
+			continue
+		}
+
+		deviation := func(du, dv, da, db int) int {
+			d := 0
+			for i, w := range []int{u, v, a, b} {
+				diff := valence(w) + []int{du, dv, da, db}[i] - target(w)
+				d += diff * diff
+			}
+			return d
+		}
+		if deviation(-1, -1, 1, 1) >= deviation(0, 0, 0, 0) {
+			continue
+		}
+
+		// the new faces must not fold over
+		before := tm.faceNormal(faces[0], nil, vec3d{}).add(tm.faceNormal(faces[1], nil, vec3d{}))
+		_, flipped := tm.flippedFaces(u, v)
+		folds := false
+		for _, face := range flipped {
+			p0, p1, p2 := tm.positions[face[0]], tm.positions[face[1]], tm.positions[face[2]]
+			if p1.sub(p0).cross(p2.sub(p0)).dot(before) <= 0 {
+				folds = true
+			}
+		}
+		if folds {
+			continue
+		}
+		tm.flipEdge(u, v)
+	}
+}
+
+// relax moves every vertex that is not on a feature towards the center of its neighbors within
+// its tangent plane and projects it back onto the original surface
+func (r *remesher) relax() {
+	tm := r.tm
+	moved := make(map[int]vec3d)
+	for v := range tm.positions {
+		if r.featureVertex[v] || len(tm.facesOf(v)) == 0 {
+			continue
+		}
+		neighbors := tm.neighbors(v)
+		center := vec3d{}
+		for _, w := range neighbors {
+			center = center.add(tm.positions[w])
+		}
+		center = center.scale(1 / float64(len(neighbors)))
+		normal := tm.vertexNormal(v)
+		d := center.sub(tm.positions[v])
+		p := tm.positions[v].add(d.sub(normal.scale(normal.dot(d))))
+		if _, closest, _, ok := r.bvh.Nearest(p.vec3()); ok {
+			p = toVec3d(closest)
+		}
+		moved[v] = p
+	}
+	// a move that would fold a face over is skipped. The moves are made in order so the result does
+	// not depend on map order
+	for v := range tm.positions {
+		if p, ok := moved[v]; ok && !tm.movingFolds(v, p) {
+			tm.positions[v] = p
+		}
+	}
+}
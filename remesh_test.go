@@ -0,0 +1,177 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+// edgeLengths returns the shortest, mean and longest edge length of the mesh
+func edgeLengths(mesh *Mesh) (float64, float64, float64) {
+	im := newIndexedMesh(mesh)
+	shortest, longest, sum := math.Inf(1), 0.0, 0.0
+	edges := im.edgeFaces()
+	for key := range edges {
+		l := toVec3d(im.vertices[key[0]]).sub(toVec3d(im.vertices[key[1]])).length()
+		shortest = math.Min(shortest, l)
+		longest = math.Max(longest, l)
+		sum += l
+	}
+	return shortest, sum / float64(len(edges)), longest
+}
+
+func TestRemeshBox(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{-1, -1, -1}, Vec3{1, 1, 1})
+	remeshed := mesh.Remesh(RemeshOptions{TargetEdgeLength: 0.25, FeatureAngle: 45})
+	report := remeshed.Analyze()
+	if !report.Watertight || report.FlippedTriangles != 0 {
+		t.Fatalf("Expected a closed, consistently oriented mesh, found: %+v", report)
+	}
+	if !closeTo(remeshed.Volume(), 8, 1e-3) {
+		t.Errorf("Expected the sharp edges of the box to be kept, found volume: %v", remeshed.Volume())
+	}
+	shortest, mean, longest := edgeLengths(remeshed)
+	if mean < 0.2 || mean > 0.3 || longest > 0.4 || shortest < 0.05 {
+		t.Errorf("Expected edges near 0.25, found %v to %v with mean %v", shortest, longest, mean)
+	}
+
+	// the box edges are creases inside a closed mesh, so the vertices along them aim for six
+	// neighbors like the rest
+	im := newIndexedMesh(remeshed)
+	tm := newTrimesh(im)
+	count, total := 0, 0
+	for v, p := range im.vertices {
+		onFace := 0
+		for _, c := range []float32{p.X, p.Y, p.Z} {
+			if c == -1 || c == 1 {
+				onFace++
+			}
+		}
+		if onFace == 2 {
+			count++
+			total += len(tm.neighbors(v))
+		}
+	}
+	if count == 0 {
+		t.Fatalf("Expected vertices along the edges of the box")
+	}
+	if mean := float64(total) / float64(count); mean < 5.75 {
+		t.Errorf("Expected vertices along the edges of the box to have about six neighbors, found: %v", mean)
+	}
+}
+
+func TestRemeshSphere(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 16, 32)
+	remeshed := mesh.Remesh(RemeshOptions{TargetEdgeLength: 0.15})
+	report := remeshed.Analyze()
+	if !report.Watertight || report.FlippedTriangles != 0 {
+		t.Fatalf("Expected a closed, consistently oriented mesh, found: %+v", report)
+	}
+	for _, tri := range remeshed.Triangles {
+		for _, v := range tri.Vertices {
+			if r := toVec3d(v).length(); math.Abs(r-1) > 0.02 {
+				t.Fatalf("Expected vertices on the sphere, found radius: %v", r)
+			}
+		}
+	}
+	// the poles of the UV sphere have long thin triangles which should be gone
+	shortest, mean, _ := edgeLengths(remeshed)
+	if mean < 0.12 || mean > 0.18 || shortest < 0.03 {
+		t.Errorf("Expected edges near 0.15, found shortest %v and mean %v", shortest, mean)
+	}
+}
+
+func TestRemeshKeepsBoundary(t *testing.T) {
+	mesh := makeGridMesh(3)
+	remeshed := mesh.Remesh(RemeshOptions{TargetEdgeLength: 0.1})
+	if len(remeshed.Holes()) != 1 {
+		t.Errorf("Expected the boundary to stay a single loop")
+	}
+	if !closeTo(remeshed.SurfaceArea(), 1, 1e-4) {
+		t.Errorf("Expected the area to be kept, found: %v", remeshed.SurfaceArea())
+	}
+	box, _ := remeshed.BoundingBox()
+	if box.Min.X != 0 || box.Min.Y != 0 || box.Max.X != 1 || box.Max.Y != 1 || box.Max.Z != 0 {
+		t.Errorf("Expected the boundary corners to stay, found: %v", box)
+	}
+	if len(remeshed.Triangles) < 150 {
+		t.Errorf("Expected the grid to be refined, found %d triangles", len(remeshed.Triangles))
+	}
+}
+
+func TestRelaxSkipsFoldingMoves(t *testing.T) {
+	// a fan around the origin folded sharply along the y axis. Its neighbors are so uneven that
+	// moving the middle vertex to their center would turn faces over
+	ring := []Vec3{{0.3, 0.4, 0}, {0, 1.2, 0}, {-3.2, 0.7, 0}, {0, -0.5, 0}, {0.8, -1.8, 0}}
+	fold := float32(150 * math.Pi / 180)
+	mesh := &Mesh{}
+	for i := range ring {
+		if ring[i].X < 0 {
+			ring[i] = Vec3{ring[i].X * float32(math.Cos(float64(fold))), ring[i].Y, -ring[i].X * float32(math.Sin(float64(fold)))}
+		}
+	}
+	for i := range ring {
+		tri := Triangle{Vertices: [3]Vec3{{}, ring[i], ring[(i+1)%len(ring)]}}
+		tri.Normal = tri.ComputeNormal()
+		mesh.Triangles = append(mesh.Triangles, tri)
+	}
+
+	im := newIndexedMesh(mesh)
+	r := &remesher{tm: newTrimesh(im), features: make(map[[2]int]bool), bvh: NewBVH(mesh), length: 1}
+	r.corner = make([]bool, len(im.vertices))
+	r.featureVertex = make([]bool, len(im.vertices))
+	for v, p := range im.vertices {
+		r.featureVertex[v] = p != (Vec3{})
+	}
+	before := make([]vec3d, len(r.tm.faces))
+	for f := range r.tm.faces {
+		before[f] = r.tm.faceNormal(f, nil, vec3d{})
+	}
+	r.relax()
+	for f := range r.tm.faces {
+		if r.tm.faceNormal(f, nil, vec3d{}).dot(before[f]) <= 0 {
+			t.Errorf("Expected face %d to keep its orientation", f)
+		}
+	}
+}
+
+func TestFlippedFacesKeepOrientation(t *testing.T) {
+	// two triangles of a square facing +z, with the edge given in either direction
+	for _, edge := range [][2]int{{0, 2}, {2, 0}} {
+		tm := newTrimesh(&indexedMesh{
+			vertices: []Vec3{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+			faces:    [][3]int{{0, 1, 2}, {0, 2, 3}},
+		})
+		_, flipped := tm.flippedFaces(edge[0], edge[1])
+		tm.flipEdge(edge[0], edge[1])
+		for f, face := range tm.faces {
+			if face != flipped[0] && face != flipped[1] {
+				t.Errorf("Expected the flipped faces %v, found: %v", flipped, face)
+			}
+			if normal := tm.faceNormal(f, nil, vec3d{}); normal.z <= 0 {
+				t.Errorf("Expected face %v to keep facing +z, found: %v", face, normal)
+			}
+		}
+		if len(tm.edgeFaces(1, 3)) != 2 {
+			t.Errorf("Expected the edge to run between the other two corners")
+		}
+	}
+}
+
+func TestRemeshStep(t *testing.T) {
+	mesh := makeStepMesh()
+	remeshed := mesh.Remesh(RemeshOptions{TargetEdgeLength: 0.2, FeatureAngle: 45})
+	checkClosed(t, remeshed)
+	if !closeTo(remeshed.Volume(), 6, 1e-3) {
+		t.Errorf("Expected the step to keep its volume, found: %v", remeshed.Volume())
+	}
+	// every face points the same way as the part of the step it lies on, including around the
+	// inside corner
+	bvh := NewBVH(mesh)
+	for i, tri := range remeshed.Triangles {
+		center := tri.Vertices[0].Add(tri.Vertices[1]).Add(tri.Vertices[2]).Scale(1.0 / 3)
+		f, _, _, _ := bvh.Nearest(center)
+		if tri.normal64().normalize().dot(mesh.Triangles[f].normal64().normalize()) <= 0 {
+			t.Fatalf("Expected triangle %d to face the same way as the step, found normal: %v", i, tri.normal64())
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package meshful
+
+// RepairOptions controls the steps of the repair pipeline
+type RepairOptions struct {
+	// vertices closer than this distance are merged. 0 only merges vertices at exactly the same
+	// position
+	WeldTolerance float32
+
+	// holes bounded by at most this many edges are filled. 0 disables hole filling
+	MaxHoleEdges int
+
+	// shells with less than this fraction of the total surface area are removed. The largest
+	// shell is always kept. 0 keeps every shell
+	MinShellAreaFraction float32
+}
+
+// DefaultRepairOptions returns options suitable for meshes measured in millimeters
+func DefaultRepairOptions() RepairOptions {
+	return RepairOptions{
+		WeldTolerance:        1e-4,
+		MaxHoleEdges:         100,
+		MinShellAreaFraction: 0.001,
+	}
+}
+
+// RepairLog records what the repair pipeline changed
+type RepairLog struct {
+	WeldedVertices             int `json:"weldedVertices"`
+	DegenerateTrianglesRemoved int `json:"degenerateTrianglesRemoved"`
+	DuplicateTrianglesRemoved  int `json:"duplicateTrianglesRemoved"`
+	ShellsRemoved              int `json:"shellsRemoved"`
+	ShellTrianglesRemoved      int `json:"shellTrianglesRemoved"`
+	TrianglesFlipped           int `json:"trianglesFlipped"`
+	HolesFilled                int `json:"holesFilled"`
+	TrianglesAdded             int `json:"trianglesAdded"`
+}
+
+// Repair returns a repaired copy of the mesh along with a log of the changes. The steps run in
+// order: weld nearby vertices, remove degenerate and duplicate triangles, remove tiny disconnected
+// shells, make the orientation consistent and outward facing and fill small holes. Normals of the
+// repaired mesh are recomputed from the winding of each triangle
+func (mesh *Mesh) Repair(opts RepairOptions) (*Mesh, RepairLog) {
+	var log RepairLog
+
+	im := newWeldedIndexedMesh(mesh, opts.WeldTolerance)
+	log.WeldedVertices = len(mesh.uniquePoints()) - len(im.vertices)
+
+	// remove degenerate triangles, including the ones collapsed by welding
+	keep := make([]bool, len(im.faces))
+	for i, face := range im.faces {
+		t := Triangle{Vertices: [3]Vec3{im.vertices[face[0]], im.vertices[face[1]], im.vertices[face[2]]}}
+		degenerate := face[0] == face[1] || face[1] == face[2] || face[2] == face[0] || t.isDegenerate()
+		keep[i] = !degenerate
+		if degenerate {
+			log.DegenerateTrianglesRemoved++
+		}
+	}
+	im.keepFaces(keep)
+
+	// remove duplicate triangles
+	keep = make([]bool, len(im.faces))
+	for i := range keep {
+		keep[i] = true
+	}
+	for _, i := range im.duplicateFaces() {
+		keep[i] = false
+		log.DuplicateTrianglesRemoved++
+	}
+	im.keepFaces(keep)
+
+	// remove tiny shells
+	if opts.MinShellAreaFraction > 0 {
+		log.ShellsRemoved, log.ShellTrianglesRemoved = im.removeSmallShells(opts.MinShellAreaFraction)
+	}
+
+	// fix the orientation
+	edges := im.edgeFaces()
+	for i, f := range im.orientationFlips(edges) {
+		if f {
+			im.faces[i][1], im.faces[i][2] = im.faces[i][2], im.faces[i][1]
+			log.TrianglesFlipped++
+		}
+	}
+
+	// fill holes
+	if opts.MaxHoleEdges > 0 {
+		edges = im.edgeFaces()
+		for _, loop := range im.boundaryLoops(edges) {
+			if len(loop) > opts.MaxHoleEdges {
+				continue
+			}
+			log.TrianglesAdded += im.fillHole(loop, false)
+			log.HolesFilled++
+		}
+	}
+
+	return im.toMesh(), log
+}
+
+// removeSmallShells removes every shell whose area is less than fraction of the total area and
+// returns how many shells and triangles were removed. The largest shell is always kept
+func (im *indexedMesh) removeSmallShells(fraction float32) (int, int) {
+	components := im.components(im.edgeFaces())
+	if len(components) < 2 {
+		return 0, 0
+	}
+
+	areas := make([]float64, len(components))
+	var total float64
+	largest := 0
+	for c, component := range components {
+		for _, i := range component {
+			face := im.faces[i]
+			t := Triangle{Vertices: [3]Vec3{im.vertices[face[0]], im.vertices[face[1]], im.vertices[face[2]]}}
+			areas[c] += float64(t.Area())
+		}
+		total += areas[c]
+		if areas[c] > areas[largest] {
+			largest = c
+		}
+	}
+
+	keep := make([]bool, len(im.faces))
+	shells, triangles := 0, 0
+	for c, component := range components {
+		remove := c != largest && areas[c] < float64(fraction)*total
+		for _, i := range component {
+			keep[i] = !remove
+		}
+		if remove {
+			shells++
+			triangles += len(component)
+		}
+	}
+	im.keepFaces(keep)
+	return shells, triangles
+}
@@ -0,0 +1,44 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{10, 10, 10})
+	// open a hole in the top, flip a triangle, nudge a vertex and add a duplicate, a degenerate
+	// triangle and a small floating shell
+	mesh.Triangles = append(mesh.Triangles[:2], mesh.Triangles[4:]...)
+	mesh.Triangles[3].Flip()
+	mesh.Triangles[0].Vertices[0].X += 1e-5
+	mesh.Triangles = append(mesh.Triangles, mesh.Triangles[5], Triangle{
+		Vertices: [3]Vec3{{0, 0, 0}, {5, 0, 0}, {10, 0, 0}},
+	})
+	mesh.Triangles = append(mesh.Triangles, makeBoxMesh(Vec3{20, 20, 20}, Vec3{20.1, 20.1, 20.1}).Triangles...)
+
+	repaired, log := mesh.Repair(DefaultRepairOptions())
+
+	if log.WeldedVertices != 1 {
+		t.Errorf("Expected 1 welded vertex, found: %d", log.WeldedVertices)
+	}
+	if log.DegenerateTrianglesRemoved != 1 || log.DuplicateTrianglesRemoved != 1 {
+		t.Errorf("Expected 1 degenerate and 1 duplicate removed, found: %+v", log)
+	}
+	if log.ShellsRemoved != 1 || log.ShellTrianglesRemoved != 12 {
+		t.Errorf("Expected the small box to be removed, found: %+v", log)
+	}
+	if log.TrianglesFlipped != 1 {
+		t.Errorf("Expected 1 flipped triangle, found: %d", log.TrianglesFlipped)
+	}
+	if log.HolesFilled != 1 {
+		t.Errorf("Expected 1 filled hole, found: %d", log.HolesFilled)
+	}
+
+	report := repaired.Analyze()
+	if !report.VolumeReliable || report.Shells != 1 {
+		t.Errorf("Expected a single watertight shell, found: %+v", report)
+	}
+	if !closeTo(repaired.Volume(), 1000, 1e-2) {
+		t.Errorf("Expected volume of 1000, found: %v", repaired.Volume())
+	}
+}
@@ -0,0 +1,126 @@
+package meshful
+
+import (
+	"math"
+	"sort"
+)
+
+// SelfIntersections returns the pairs of triangles that intersect each other. Triangles that
+// share an edge or a vertex only count when they cross beyond the shared part, and exact
+// duplicates are not reported since Analyze already counts them. Candidate pairs are found with a
+// BVH so only triangles whose bounding boxes overlap are tested. Each pair holds the lower
+// triangle index first and the pairs are sorted
+func (mesh *Mesh) SelfIntersections() [][2]int {
+	bvh := NewBVH(mesh)
+	pairs := [][2]int{}
+	for i := range mesh.Triangles {
+		candidates := bvh.QueryBox(mesh.Triangles[i].boundingBox())
+		sort.Ints(candidates)
+		for _, j := range candidates {
+			if j <= i {
+				continue
+			}
+			if trianglesSelfIntersect(&mesh.Triangles[i], &mesh.Triangles[j]) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	return pairs
+}
+
+// boundingBox returns the axis-aligned box around the triangle
+func (t *Triangle) boundingBox() AABB {
+	box := AABB{Min: t.Vertices[0], Max: t.Vertices[0]}
+	return box.Expand(t.Vertices[1]).Expand(t.Vertices[2])
+}
+
+// trianglesSelfIntersect reports whether two triangles of the same mesh intersect anywhere other
+// than along the vertices and edges they share
+func trianglesSelfIntersect(t1, t2 *Triangle) bool {
+	// find the shared vertices and reorder both triangles so they come first
+	var a, b [3]vec3d
+	shared := 0
+	var restA, restB []vec3d
+	usedB := [3]bool{}
+	for _, va := range t1.Vertices {
+		match := -1
+		for j, vb := range t2.Vertices {
+			if !usedB[j] && va == vb {
+				match = j
+				break
+			}
+		}
+		if match >= 0 {
+			usedB[match] = true
+			a[shared] = toVec3d(va)
+			b[shared] = toVec3d(va)
+			shared++
+		} else {
+			restA = append(restA, toVec3d(va))
+		}
+	}
+	for j, vb := range t2.Vertices {
+		if !usedB[j] {
+			restB = append(restB, toVec3d(vb))
+		}
+	}
+	copy(a[shared:], restA)
+	copy(b[shared:], restB)
+
+	switch shared {
+	case 3:
+		return false
+	case 2:
+		// neighbors across an edge only intersect if they fold onto each other
+		if orient3d(a[0], a[1], a[2], b[2]) != 0 {
+			return false
+		}
+		edge := a[1].sub(a[0])
+		return edge.cross(a[2].sub(a[0])).dot(edge.cross(b[2].sub(a[0]))) > 0
+	case 1:
+		kind, segment := triangleIntersection(a, b)
+		if kind == segmentIntersection {
+			// the triangles always touch at the shared vertex, anything longer is a crossing
+			scale := math.Max(triangleScale(a), triangleScale(b))
+			return segment[1].sub(segment[0]).length() > scale*1e-9
+		}
+		if kind == coplanarTriangles {
+			return coplanarSharedVertexOverlap(a, b)
+		}
+		return false
+	default:
+		kind, _ := triangleIntersection(a, b)
+		if kind == coplanarTriangles {
+			return coplanarOverlap(a, b)
+		}
+		return kind == segmentIntersection
+	}
+}
+
+// coplanarSharedVertexOverlap reports whether two coplanar triangles that share their first
+// vertex overlap anywhere else
+func coplanarSharedVertexOverlap(a, b [3]vec3d) bool {
+	pa, pb := projectCoplanar(a, b)
+	// the edges opposite the shared vertex crossing any edge of the other triangle
+	for j := 0; j < 3; j++ {
+		if segmentsCross2D(pa[1], pa[2], pb[j], pb[(j+1)%3]) || segmentsCross2D(pb[1], pb[2], pa[j], pa[(j+1)%3]) {
+			return true
+		}
+	}
+	for i := 1; i < 3; i++ {
+		if pointStrictlyInTriangle2D(pa[i], pb) || pointStrictlyInTriangle2D(pb[i], pa) {
+			return true
+		}
+	}
+	// the corners at the shared vertex overlapping, tested with a point just inside each corner
+	inside := func(t [3]vec2d) vec2d {
+		mid := vec2d{(t[1].x + t[2].x) / 2, (t[1].y + t[2].y) / 2}
+		return vec2d{t[0].x + (mid.x-t[0].x)*1e-3, t[0].y + (mid.y-t[0].y)*1e-3}
+	}
+	return pointStrictlyInTriangle2D(inside(pa), pb) || pointStrictlyInTriangle2D(inside(pb), pa)
+}
+
+// triangleScale returns the length of the longest edge of the triangle
+func triangleScale(t [3]vec3d) float64 {
+	return math.Max(t[1].sub(t[0]).length(), math.Max(t[2].sub(t[1]).length(), t[0].sub(t[2]).length()))
+}
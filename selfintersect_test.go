@@ -0,0 +1,54 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestSelfIntersectionsOfCleanMesh(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 12, 24)
+	if pairs := mesh.SelfIntersections(); len(pairs) != 0 {
+		t.Errorf("Expected no self-intersections, found: %v", pairs)
+	}
+}
+
+func TestSelfIntersectionsOfOverlappingBoxes(t *testing.T) {
+	a := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 2, 2})
+	b := makeBoxMesh(Vec3{1, 1, 1}, Vec3{3, 3, 3})
+	mesh := &Mesh{Triangles: append(a.Triangles, b.Triangles...)}
+
+	pairs := mesh.SelfIntersections()
+	if len(pairs) == 0 {
+		t.Fatal("Expected overlapping boxes to intersect")
+	}
+	for _, p := range pairs {
+		if p[0] >= 12 || p[1] < 12 {
+			t.Errorf("Expected only pairs between the two boxes, found: %v", p)
+		}
+	}
+}
+
+func TestSelfIntersectionOfAdjacentTriangles(t *testing.T) {
+	shared := [3]Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	cases := []struct {
+		name      string
+		other     [3]Vec3
+		intersect bool
+	}{
+		{"edge neighbor", [3]Vec3{{1, 0, 0}, {0, 0, 0}, {0, -1, 0}}, false},
+		{"folded edge neighbor", [3]Vec3{{1, 0, 0}, {0, 0, 0}, {0.5, 0.5, 0}}, true},
+		{"vertex neighbor", [3]Vec3{{0, 0, 0}, {-1, 0, 1}, {0, -1, 1}}, false},
+		{"vertex neighbor crossing", [3]Vec3{{0, 0, 0}, {0.5, 0.5, 1}, {0.5, 0.5, -1}}, true},
+		{"coplanar vertex neighbor", [3]Vec3{{0, 0, 0}, {-1, 0, 0}, {0, -1, 0}}, false},
+		{"coplanar vertex neighbor overlap", [3]Vec3{{0, 0, 0}, {1, 1, 0}, {2, 0.5, 0}}, true},
+		{"piercing", [3]Vec3{{0.2, 0.2, -1}, {0.3, 0.2, 1}, {0.2, 0.3, 1}}, true},
+		{"separate", [3]Vec3{{0, 0, 1}, {1, 0, 1}, {0, 1, 1}}, false},
+	}
+
+	for _, c := range cases {
+		mesh := &Mesh{Triangles: []Triangle{{Vertices: shared}, {Vertices: c.other}}}
+		found := len(mesh.SelfIntersections()) > 0
+		if found != c.intersect {
+			t.Errorf("%s: expected intersection %v, found %v", c.name, c.intersect, found)
+		}
+	}
+}
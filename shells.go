@@ -0,0 +1,50 @@
+package meshful
+
+// Connectivity selects which triangles count as connected when splitting a mesh into shells
+type Connectivity int
+
+const (
+	// EdgeConnectivity connects triangles that share an edge
+	EdgeConnectivity Connectivity = iota
+	// VertexConnectivity connects triangles that share at least one vertex
+	VertexConnectivity
+)
+
+// Shells splits the mesh into its connected pieces and returns each piece as its own mesh, so
+// the volume and area of each part can be measured separately with Volume and SurfaceArea. The
+// shells are ordered by the first triangle that belongs to them and the triangles keep their
+// normals and colors
+func (mesh *Mesh) Shells(connectivity Connectivity) []*Mesh {
+	im := newIndexedMesh(mesh)
+
+	var components [][]int
+	if connectivity == VertexConnectivity {
+		components = im.vertexComponents()
+	} else {
+		components = im.components(im.edgeFaces())
+	}
+
+	shells := make([]*Mesh, len(components))
+	for c, component := range components {
+		shell := &Mesh{Triangles: make([]Triangle, len(component))}
+		for i, t := range component {
+			shell.Triangles[i] = mesh.Triangles[t]
+		}
+		shells[c] = shell
+	}
+	return shells
+}
+
+// Merge combines meshes into a single mesh containing a copy of every triangle. It is the
+// inverse of Shells
+func Merge(meshes ...*Mesh) *Mesh {
+	count := 0
+	for _, m := range meshes {
+		count += len(m.Triangles)
+	}
+	merged := &Mesh{Triangles: make([]Triangle, 0, count)}
+	for _, m := range meshes {
+		merged.Triangles = append(merged.Triangles, m.Triangles...)
+	}
+	return merged
+}
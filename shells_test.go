@@ -0,0 +1,34 @@
+package meshful
+
+import (
+	"testing"
+)
+
+func TestShellsAndMerge(t *testing.T) {
+	small := makeBoxMesh(Vec3{0, 0, 0}, Vec3{1, 1, 1})
+	large := makeBoxMesh(Vec3{5, 0, 0}, Vec3{7, 2, 2})
+	// shares only a corner with the small box
+	corner := makeBoxMesh(Vec3{1, 1, 1}, Vec3{2, 2, 2})
+	mesh := Merge(small, large, corner)
+	if len(mesh.Triangles) != 36 {
+		t.Fatalf("Expected 36 merged triangles, found: %d", len(mesh.Triangles))
+	}
+
+	shells := mesh.Shells(EdgeConnectivity)
+	if len(shells) != 3 {
+		t.Fatalf("Expected 3 edge connected shells, found: %d", len(shells))
+	}
+	expected := []float32{1, 8, 1}
+	for i, shell := range shells {
+		if !closeTo(shell.Volume(), expected[i], 1e-5) {
+			t.Errorf("Expected shell %d volume of %v, found: %v", i, expected[i], shell.Volume())
+		}
+	}
+	if !closeTo(shells[1].SurfaceArea(), 24, 1e-5) {
+		t.Errorf("Expected large shell area of 24, found: %v", shells[1].SurfaceArea())
+	}
+
+	if shells := mesh.Shells(VertexConnectivity); len(shells) != 2 {
+		t.Errorf("Expected 2 vertex connected shells, found: %d", len(shells))
+	}
+}
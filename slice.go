@@ -0,0 +1,309 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidPlane is used when a plane has a zero normal
+var ErrInvalidPlane = errors.New("Plane normal must not be zero")
+
+// A Plane is the set of points that are perpendicular to Normal when measured from Origin. Normal
+// does not need to be normalized
+type Plane struct {
+	Origin Vec3
+	Normal Vec3
+}
+
+// Elevation returns the distance of the plane from the origin along its normal, which is the
+// height of the plane for horizontal slices
+func (plane Plane) Elevation() float32 {
+	length := math.Sqrt(plane.Normal.Dot(plane.Normal))
+	if length == 0 {
+		return 0
+	}
+	return float32(plane.Normal.Dot(plane.Origin) / length)
+}
+
+// A Vec2 represents a point in the 2D coordinates of a slice plane
+type Vec2 struct {
+	X, Y float32
+}
+
+// A Contour is a closed polyline where a plane cuts through the surface of a mesh. The last point
+// connects back to the first. Outer contours wind counter-clockwise and holes wind clockwise when
+// seen from the side the plane normal points to
+type Contour struct {
+	Points []Vec2
+	Hole   bool
+
+	// the 3D position of each point
+	points3 []Vec3
+}
+
+// Area returns the signed area enclosed by the contour, positive for outer contours and negative
+// for holes
+func (c *Contour) Area() float32 {
+	var area float64
+	for i := range c.Points {
+		a := c.Points[i]
+		b := c.Points[(i+1)%len(c.Points)]
+		area += float64(a.X)*float64(b.Y) - float64(b.X)*float64(a.Y)
+	}
+	return float32(area / 2)
+}
+
+// A Slice holds the contours where a plane cuts a mesh. 2D points are measured from the plane
+// origin along the U and V axes, which together with the plane normal form a right-handed frame
+type Slice struct {
+	Plane    Plane
+	U, V     Vec3
+	Contours []Contour
+}
+
+// Area returns the area of material in the slice, which is the area of the outer contours minus
+// the area of the holes
+func (s *Slice) Area() float32 {
+	var area float32
+	for i := range s.Contours {
+		area += s.Contours[i].Area()
+	}
+	return area
+}
+
+// To3D converts a point in the slice coordinates back into a 3D point on the plane
+func (s *Slice) To3D(p Vec2) Vec3 {
+	return s.Plane.Origin.Add(s.U.Scale(p.X)).Add(s.V.Scale(p.Y))
+}
+
+// Slice cuts the mesh with a plane and returns the closed contours of the cross section. Contours
+// are chained through the mesh edges they cross so a watertight mesh gives exactly closed loops.
+// Chains that cannot be closed because the mesh has holes are closed with a straight segment.
+// Vertices that lie exactly on the plane are treated as being slightly above it so that every
+// contour point lies on a crossing edge
+func (mesh *Mesh) Slice(plane Plane) (*Slice, error) {
+	if plane.Normal == (Vec3{}) {
+		return nil, ErrInvalidPlane
+	}
+	return mesh.sliceTriangles(plane, nil), nil
+}
+
+// SliceLayers cuts the mesh into horizontal layers of the given thickness and returns the slice
+// through the middle of each layer, starting at the bottom of the mesh
+func (mesh *Mesh) SliceLayers(layerHeight float32) ([]*Slice, error) {
+	if layerHeight <= 0 {
+		return nil, errors.New("Layer height must be positive")
+	}
+	box, err := mesh.BoundingBox()
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(math.Ceil(float64((box.Max.Z - box.Min.Z) / layerHeight)))
+	if count == 0 {
+		count = 1
+	}
+	heights := make([]float32, count)
+	for i := range heights {
+		heights[i] = box.Min.Z + (float32(i)+0.5)*layerHeight
+	}
+
+	// bucket the triangles by the layers they span so each layer only looks at its own triangles
+	buckets := make([][]int, count)
+	for i, t := range mesh.Triangles {
+		lo := min32(t.Vertices[0].Z, min32(t.Vertices[1].Z, t.Vertices[2].Z))
+		hi := max32(t.Vertices[0].Z, max32(t.Vertices[1].Z, t.Vertices[2].Z))
+		first := int(math.Floor(float64((lo-box.Min.Z)/layerHeight - 0.5)))
+		last := int(math.Ceil(float64((hi-box.Min.Z)/layerHeight - 0.5)))
+		for l := first; l <= last; l++ {
+			if l >= 0 && l < count {
+				buckets[l] = append(buckets[l], i)
+			}
+		}
+	}
+
+	slices := make([]*Slice, count)
+	for l, z := range heights {
+		plane := Plane{Origin: Vec3{0, 0, z}, Normal: Vec3{0, 0, 1}}
+		slices[l] = mesh.sliceTriangles(plane, buckets[l])
+	}
+	return slices, nil
+}
+
+// sliceSegment is the part of a contour inside one triangle, running between two crossed edges
+type sliceSegment struct {
+	from, to   [2]Vec3
+	start, end vec3d
+	used       bool
+}
+
+// sliceTriangles slices the given triangles, or every triangle if indices is nil
+func (mesh *Mesh) sliceTriangles(plane Plane, indices []int) *Slice {
+	normal := toVec3d(plane.Normal).normalize()
+	offset := normal.dot(toVec3d(plane.Origin))
+	u, v := planeBasis(normal)
+	slice := &Slice{Plane: plane, U: u.vec3(), V: v.vec3()}
+
+	segments := []sliceSegment{}
+	addTriangle := func(t *Triangle) {
+		var above [3]bool
+		count := 0
+		for i, p := range t.Vertices {
+			above[i] = normal.dot(toVec3d(p))-offset >= 0
+			if above[i] {
+				count++
+			}
+		}
+		if count == 0 || count == 3 {
+			return
+		}
+
+		// walking around the triangle the contour enters where it goes from above to below and
+		// leaves where it goes from below to above, which makes outer contours counter-clockwise
+		var seg sliceSegment
+		for i := 0; i < 3; i++ {
+			j := (i + 1) % 3
+			if above[i] == above[j] {
+				continue
+			}
+			key := sortedEdge(t.Vertices[i], t.Vertices[j])
+			point := edgePlanePoint(toVec3d(key[0]), toVec3d(key[1]), normal, offset)
+			if above[i] {
+				seg.from, seg.start = key, point
+			} else {
+				seg.to, seg.end = key, point
+			}
+		}
+		segments = append(segments, seg)
+	}
+	if indices == nil {
+		for i := range mesh.Triangles {
+			addTriangle(&mesh.Triangles[i])
+		}
+	} else {
+		for _, i := range indices {
+			addTriangle(&mesh.Triangles[i])
+		}
+	}
+
+	// chain segments where one leaves through the edge the next enters through
+	byStart := make(map[[2]Vec3][]int)
+	byEnd := make(map[[2]Vec3][]int)
+	for i, seg := range segments {
+		byStart[seg.from] = append(byStart[seg.from], i)
+		byEnd[seg.to] = append(byEnd[seg.to], i)
+	}
+	unused := func(candidates []int) int {
+		for _, i := range candidates {
+			if !segments[i].used {
+				return i
+			}
+		}
+		return -1
+	}
+	next := func(edge [2]Vec3) int {
+		return unused(byStart[edge])
+	}
+
+	for first := range segments {
+		if segments[first].used {
+			continue
+		}
+		// walk back to the start of an open chain so it is collected in one piece. A closed loop
+		// leads back around to the first segment
+		head := first
+		visited := map[int]bool{first: true}
+		for {
+			previous := unused(byEnd[segments[head].from])
+			if previous < 0 || visited[previous] {
+				break
+			}
+			visited[previous] = true
+			head = previous
+		}
+
+		points := []vec3d{}
+		current, last := head, head
+		for current >= 0 {
+			seg := &segments[current]
+			seg.used = true
+			points = append(points, seg.start)
+			last, current = current, next(seg.to)
+		}
+		if segments[last].to != segments[head].from {
+			// an open chain also ends with the point where it leaves its last triangle
+			points = append(points, segments[last].end)
+		}
+		if contour, ok := makeContour(points, toVec3d(plane.Origin), u, v); ok {
+			slice.Contours = append(slice.Contours, contour)
+		}
+	}
+
+	classifyContours(slice.Contours)
+	return slice
+}
+
+// makeContour removes repeated points from a loop and projects it into the plane
+func makeContour(points []vec3d, origin, u, v vec3d) (Contour, bool) {
+	contour := Contour{}
+	for i, p := range points {
+		if i > 0 && p == points[i-1] {
+			continue
+		}
+		contour.points3 = append(contour.points3, p.vec3())
+		d := p.sub(origin)
+		contour.Points = append(contour.Points, Vec2{float32(d.dot(u)), float32(d.dot(v))})
+	}
+	for len(contour.points3) > 1 && contour.points3[0] == contour.points3[len(contour.points3)-1] {
+		contour.points3 = contour.points3[:len(contour.points3)-1]
+		contour.Points = contour.Points[:len(contour.Points)-1]
+	}
+	return contour, len(contour.Points) >= 3
+}
+
+// classifyContours marks contours nested inside an odd number of other contours as holes and makes
+// outer contours counter-clockwise and holes clockwise
+func classifyContours(contours []Contour) {
+	for i := range contours {
+		depth := 0
+		for j := range contours {
+			if i != j && pointInPolygon(contours[i].Points[0], contours[j].Points) {
+				depth++
+			}
+		}
+		contours[i].Hole = depth%2 == 1
+		if (contours[i].Area() < 0) != contours[i].Hole {
+			contours[i].reverse()
+		}
+	}
+}
+
+func (c *Contour) reverse() {
+	for i, j := 0, len(c.Points)-1; i < j; i, j = i+1, j-1 {
+		c.Points[i], c.Points[j] = c.Points[j], c.Points[i]
+		c.points3[i], c.points3[j] = c.points3[j], c.points3[i]
+	}
+}
+
+// pointInPolygon reports whether p is inside the polygon using the even-odd rule
+func pointInPolygon(p Vec2, poly []Vec2) bool {
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// sortedEdge orders the endpoints of an edge so both triangles sharing it agree on its key
+func sortedEdge(a, b Vec3) [2]Vec3 {
+	if b.X < a.X || (b.X == a.X && (b.Y < a.Y || (b.Y == a.Y && b.Z < a.Z))) {
+		a, b = b, a
+	}
+	return [2]Vec3{a, b}
+}
@@ -0,0 +1,125 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSliceBox(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 3, 4})
+	slice, err := mesh.Slice(Plane{Origin: Vec3{0, 0, 1}, Normal: Vec3{0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slice.Contours) != 1 {
+		t.Fatalf("Expected 1 contour, found: %d", len(slice.Contours))
+	}
+	contour := slice.Contours[0]
+	if contour.Hole {
+		t.Errorf("Expected the contour to be an outer contour")
+	}
+	if !closeTo(contour.Area(), 6, 1e-5) {
+		t.Errorf("Expected a counter-clockwise contour with area 6, found: %v", contour.Area())
+	}
+	for _, p := range contour.Points {
+		q := slice.To3D(p)
+		if !closeTo(q.Z, 1, 1e-6) || q.X < -1e-6 || q.X > 2+1e-6 || q.Y < -1e-6 || q.Y > 3+1e-6 {
+			t.Errorf("Expected contour points on the box walls, found: %v", q)
+		}
+	}
+
+	// an oblique plane through the middle of the box
+	slice, err = mesh.Slice(Plane{Origin: Vec3{1, 1.5, 2}, Normal: Vec3{1, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slice.Contours) != 1 || !closeTo(slice.Area(), 3*2*float32(math.Sqrt2), 1e-5) {
+		t.Errorf("Expected an oblique cross section of area %v, found: %v", 6*math.Sqrt2, slice.Area())
+	}
+
+	if _, err := mesh.Slice(Plane{}); err != ErrInvalidPlane {
+		t.Errorf("Expected ErrInvalidPlane, found: %v", err)
+	}
+}
+
+func TestSliceHoles(t *testing.T) {
+	inner := makeBoxMesh(Vec3{1, 1, 1}, Vec3{2, 2, 2})
+	for i := range inner.Triangles {
+		inner.Triangles[i].Flip()
+	}
+	mesh := Merge(makeBoxMesh(Vec3{0, 0, 0}, Vec3{3, 3, 3}), inner)
+
+	slice, err := mesh.Slice(Plane{Origin: Vec3{0, 0, 1.5}, Normal: Vec3{0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slice.Contours) != 2 {
+		t.Fatalf("Expected 2 contours, found: %d", len(slice.Contours))
+	}
+	holes := 0
+	for _, c := range slice.Contours {
+		if c.Hole {
+			holes++
+			if c.Area() >= 0 {
+				t.Errorf("Expected the hole to wind clockwise")
+			}
+		}
+	}
+	if holes != 1 {
+		t.Errorf("Expected 1 hole, found: %d", holes)
+	}
+	if !closeTo(slice.Area(), 8, 1e-5) {
+		t.Errorf("Expected a net area of 8, found: %v", slice.Area())
+	}
+}
+
+func TestSliceOpenMesh(t *testing.T) {
+	// a box without its left wall, with the right wall first so slicing starts in the middle of the
+	// open chain
+	box := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 3, 4})
+	mesh := &Mesh{}
+	for _, i := range []int{8, 9, 0, 1, 2, 3, 4, 5, 6, 7} {
+		mesh.Triangles = append(mesh.Triangles, box.Triangles[i])
+	}
+
+	slice, err := mesh.Slice(Plane{Origin: Vec3{0, 0, 1}, Normal: Vec3{0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slice.Contours) != 1 {
+		t.Fatalf("Expected the open chain to give 1 contour, found: %d", len(slice.Contours))
+	}
+	// the missing wall is closed with a straight segment
+	if !closeTo(slice.Area(), 6, 1e-5) {
+		t.Errorf("Expected a closed rectangle of area 6, found: %v", slice.Area())
+	}
+}
+
+func TestSliceLayers(t *testing.T) {
+	mesh := makeSphereMesh(Vec3{0, 0, 0}, 1, 32, 64)
+	slices, err := mesh.SliceLayers(0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slices) != 10 {
+		t.Fatalf("Expected 10 layers, found: %d", len(slices))
+	}
+	for i, slice := range slices {
+		z := slice.Plane.Origin.Z
+		if !closeTo(z, -0.9+0.2*float32(i), 1e-5) {
+			t.Errorf("Expected layer %d at %v, found: %v", i, -0.9+0.2*float32(i), z)
+		}
+		if len(slice.Contours) != 1 {
+			t.Errorf("Expected 1 contour in layer %d, found: %d", i, len(slice.Contours))
+			continue
+		}
+		expected := math.Pi * (1 - float64(z)*float64(z))
+		if math.Abs(float64(slice.Area())-expected) > 0.03*expected {
+			t.Errorf("Expected layer %d area near %v, found: %v", i, expected, slice.Area())
+		}
+	}
+
+	if _, err := mesh.SliceLayers(0); err == nil {
+		t.Errorf("Expected an error for a zero layer height")
+	}
+}
@@ -0,0 +1,152 @@
+package meshful
+
+// SmoothingMethod selects how Smooth moves each vertex towards its neighbors
+type SmoothingMethod int
+
+const (
+	// LaplacianSmoothing moves each vertex towards the average of its neighbors. It also shrinks
+	// the mesh
+	LaplacianSmoothing SmoothingMethod = iota
+	// CotangentSmoothing weights the neighbors with the cotangent weights of the surface, which
+	// moves vertices across the surface much less and so keeps the shape of the triangles
+	CotangentSmoothing
+	// TaubinSmoothing follows every shrinking Laplacian step with an inflating one, which removes
+	// noise without shrinking the mesh
+	TaubinSmoothing
+)
+
+// SmoothOptions controls how Smooth moves the vertices
+type SmoothOptions struct {
+	Method     SmoothingMethod
+	Iterations int
+
+	// fraction of the way each vertex moves towards its neighbors in a step. 0 uses 0.5
+	Lambda float32
+
+	// step of the inflating pass of Taubin smoothing, which must be negative with a magnitude
+	// slightly larger than Lambda. 0 picks the value for a pass-band frequency of 0.1
+	Mu float32
+
+	// keep vertices on boundary and non-manifold edges in place
+	PinBoundary bool
+
+	// keep the vertices for which the function returns true in place. May be nil
+	Pinned func(Vec3) bool
+}
+
+// Smooth returns a copy of the mesh with the vertices moved towards their neighbors to remove
+// noise. The connectivity of the mesh does not change, and normals are recomputed
+func (mesh *Mesh) Smooth(opts SmoothOptions) *Mesh {
+	im := newIndexedMesh(mesh)
+	lambda := float64(opts.Lambda)
+	if lambda == 0 {
+		lambda = 0.5
+	}
+	mu := float64(opts.Mu)
+	if mu == 0 {
+		// 1/lambda + 1/mu is the pass-band frequency below which shapes are kept
+		mu = 1 / (0.1 - 1/lambda)
+	}
+
+	pinned := make([]bool, len(im.vertices))
+	edges := im.edgeFaces()
+	if opts.PinBoundary {
+		for key, faces := range edges {
+			if len(faces) != 2 {
+				pinned[key[0]] = true
+				pinned[key[1]] = true
+			}
+		}
+	}
+	if opts.Pinned != nil {
+		for i, v := range im.vertices {
+			pinned[i] = pinned[i] || opts.Pinned(v)
+		}
+	}
+
+	positions := make([]vec3d, len(im.vertices))
+	for i, v := range im.vertices {
+		positions[i] = toVec3d(v)
+	}
+	ring := make([][]int, len(positions))
+	for _, key := range sortedEdgeKeys(edges) {
+		ring[key[0]] = append(ring[key[0]], key[1])
+		ring[key[1]] = append(ring[key[1]], key[0])
+	}
+
+	step := func(factor float64, cotangent bool) {
+		var weights map[[2]int]float64
+		if cotangent {
+			weights = cotangentWeights(positions, im.faces)
+		}
+		moved := make([]vec3d, len(positions))
+		for v, p := range positions {
+			moved[v] = p
+			if pinned[v] || len(ring[v]) == 0 {
+				continue
+			}
+			sum, total := vec3d{}, 0.0
+			for _, w := range ring[v] {
+				weight := 1.0
+				if cotangent {
+					weight = weights[edgeKey(v, w)]
+				}
+				sum = sum.add(positions[w].scale(weight))
+				total += weight
+			}
+			if total <= 0 {
+				// the cotangent weights break down around very obtuse triangles
+				sum, total = vec3d{}, 0
+				for _, w := range ring[v] {
+					sum = sum.add(positions[w])
+					total++
+				}
+			}
+			average := sum.scale(1 / total)
+			moved[v] = p.add(average.sub(p).scale(factor))
+		}
+		positions = moved
+	}
+
+	for i := 0; i < opts.Iterations; i++ {
+		switch opts.Method {
+		case CotangentSmoothing:
+			step(lambda, true)
+		case TaubinSmoothing:
+			step(lambda, false)
+			step(mu, false)
+		default:
+			step(lambda, false)
+		}
+	}
+
+	for i, p := range positions {
+		im.vertices[i] = p.vec3()
+	}
+	return im.toMesh()
+}
+
+// cotangentWeights returns the weight of every edge, half the sum of the cotangents of the angles
+// opposite it. Negative weights from obtuse angles are clamped to zero
+func cotangentWeights(positions []vec3d, faces [][3]int) map[[2]int]float64 {
+	weights := make(map[[2]int]float64)
+	for _, face := range faces {
+		for k := 0; k < 3; k++ {
+			a, b, c := face[k], face[(k+1)%3], face[(k+2)%3]
+			// the angle at c is opposite the edge ab
+			u := positions[a].sub(positions[c])
+			v := positions[b].sub(positions[c])
+			sine := u.cross(v).length()
+			if sine == 0 {
+				continue
+			}
+			weights[edgeKey(a, b)] += u.dot(v) / sine / 2
+		}
+	}
+	for key, w := range weights {
+		if w < 0 {
+			weights[key] = 0
+		}
+	}
+	return weights
+}
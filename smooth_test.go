@@ -0,0 +1,96 @@
+package meshful
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// addNoise moves every vertex of the mesh by a random offset of up to amplitude along each axis,
+// keeping shared vertices together
+func addNoise(mesh *Mesh, amplitude float32) *Mesh {
+	r := rand.New(rand.NewSource(1))
+	moved := make(map[Vec3]Vec3)
+	noisy := &Mesh{}
+	for _, t := range mesh.Triangles {
+		for i, v := range t.Vertices {
+			m, ok := moved[v]
+			if !ok {
+				m = v.Add(Vec3{
+					amplitude * (2*r.Float32() - 1),
+					amplitude * (2*r.Float32() - 1),
+					amplitude * (2*r.Float32() - 1),
+				})
+				moved[v] = m
+			}
+			t.Vertices[i] = m
+		}
+		noisy.Triangles = append(noisy.Triangles, t)
+	}
+	return noisy
+}
+
+// radiusSpread returns the standard deviation of the distance of the vertices from the origin
+func radiusSpread(mesh *Mesh) float64 {
+	var sum, squares, n float64
+	for _, t := range mesh.Triangles {
+		for _, v := range t.Vertices {
+			r := toVec3d(v).length()
+			sum += r
+			squares += r * r
+			n++
+		}
+	}
+	mean := sum / n
+	return math.Sqrt(squares/n - mean*mean)
+}
+
+func TestSmoothingMethods(t *testing.T) {
+	sphere := makeSphereMesh(Vec3{0, 0, 0}, 1, 24, 48)
+	noisy := addNoise(sphere, 0.03)
+	noise := radiusSpread(noisy)
+
+	for _, method := range []SmoothingMethod{LaplacianSmoothing, CotangentSmoothing, TaubinSmoothing} {
+		smooth := noisy.Smooth(SmoothOptions{Method: method, Iterations: 3})
+		if len(smooth.Triangles) != len(noisy.Triangles) || !smooth.Analyze().Watertight {
+			t.Errorf("Expected method %d to keep the connectivity", method)
+		}
+		if spread := radiusSpread(smooth); spread >= 0.6*noise {
+			t.Errorf("Expected method %d to remove noise, found spread %v from %v", method, spread, noise)
+		}
+	}
+
+	volumes := make(map[SmoothingMethod]float32)
+	for _, method := range []SmoothingMethod{LaplacianSmoothing, TaubinSmoothing} {
+		volumes[method] = noisy.Smooth(SmoothOptions{Method: method, Iterations: 10}).Volume()
+	}
+	original := sphere.Volume()
+	if volumes[LaplacianSmoothing] >= 0.95*original {
+		t.Errorf("Expected Laplacian smoothing to shrink the sphere, found volume: %v", volumes[LaplacianSmoothing])
+	}
+	if math.Abs(float64(volumes[TaubinSmoothing]/original-1)) > 0.03 {
+		t.Errorf("Expected Taubin smoothing to keep the volume near %v, found: %v", original, volumes[TaubinSmoothing])
+	}
+}
+
+func TestSmoothPinning(t *testing.T) {
+	grid := addNoise(makeGridMesh(10), 0.01)
+	smooth := grid.Smooth(SmoothOptions{Iterations: 5, PinBoundary: true})
+	before, _ := grid.BoundingBox()
+	after, _ := smooth.BoundingBox()
+	if before.Min.X != after.Min.X || before.Max.Y != after.Max.Y {
+		t.Errorf("Expected the boundary to stay in place, found %v instead of %v", after, before)
+	}
+
+	sphere := addNoise(makeSphereMesh(Vec3{0, 0, 0}, 1, 12, 24), 0.03)
+	pinned := func(v Vec3) bool { return v.Z > 0 }
+	smooth = sphere.Smooth(SmoothOptions{Method: TaubinSmoothing, Iterations: 5, Pinned: pinned})
+	for i, tri := range smooth.Triangles {
+		for j, v := range tri.Vertices {
+			original := sphere.Triangles[i].Vertices[j]
+			if pinned(original) && v != original {
+				t.Errorf("Expected pinned vertex %v to stay, found: %v", original, v)
+			}
+		}
+	}
+}
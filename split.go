@@ -0,0 +1,227 @@
+package meshful
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidBuildVolume is used when a build volume has no size along some axis
+var ErrInvalidBuildVolume = errors.New("Build volume must have a positive size along every axis")
+
+// pinSegments is the number of sides of the polygon used for pin pockets
+const pinSegments = 16
+
+// SplitOptions controls how SplitToFit divides a part
+type SplitOptions struct {
+	// radius of the alignment pin pockets added to both sides of every cut. No pockets are added
+	// when it is 0
+	PinRadius float32
+	// depth of each pocket, which is half the length of the pin. Defaults to twice the radius
+	PinDepth float32
+}
+
+// SplitToFit cuts the mesh with axis-aligned planes until every piece fits inside the build
+// volume without being rotated. Pieces that are too large are cut across the axis where they
+// overflow the most, into as many equal slabs as that axis needs, and the halves are split again
+// until they fit. Every cut is capped so a watertight mesh gives watertight pieces. When
+// PinRadius is set a pocket is sunk into both faces of each cut region that is thick enough to
+// hold a pin, so the pieces can be lined up with dowels when they are glued back together
+func (mesh *Mesh) SplitToFit(volume AABB, opts SplitOptions) ([]*Mesh, error) {
+	limit := volume.Size()
+	if limit.X <= 0 || limit.Y <= 0 || limit.Z <= 0 {
+		return nil, ErrInvalidBuildVolume
+	}
+	if opts.PinRadius > 0 && opts.PinDepth <= 0 {
+		opts.PinDepth = 2 * opts.PinRadius
+	}
+	if _, err := mesh.BoundingBox(); err != nil {
+		return nil, err
+	}
+
+	pieces := []*Mesh{}
+	var split func(piece *Mesh) error
+	split = func(piece *Mesh) error {
+		box, _ := piece.BoundingBox()
+		size := box.Size()
+
+		// cut across the axis that is the furthest over the limit
+		axis, worst := -1, float32(1)
+		for i := 0; i < 3; i++ {
+			if ratio := component(size, i) / component(limit, i); ratio > worst {
+				axis, worst = i, ratio
+			}
+		}
+		if axis < 0 {
+			pieces = append(pieces, piece)
+			return nil
+		}
+
+		slabs := float32(math.Ceil(float64(worst)))
+		var plane Plane
+		switch axis {
+		case 0:
+			plane = Plane{Origin: Vec3{box.Min.X + size.X/slabs, 0, 0}, Normal: Vec3{1, 0, 0}}
+		case 1:
+			plane = Plane{Origin: Vec3{0, box.Min.Y + size.Y/slabs, 0}, Normal: Vec3{0, 1, 0}}
+		default:
+			plane = Plane{Origin: Vec3{0, 0, box.Min.Z + size.Z/slabs}, Normal: Vec3{0, 0, 1}}
+		}
+
+		above, below, err := piece.cut(plane, opts.PinRadius, opts.PinDepth)
+		if err != nil {
+			return err
+		}
+		if err := split(below); err != nil {
+			return err
+		}
+		return split(above)
+	}
+	if err := split(mesh); err != nil {
+		return nil, err
+	}
+	return pieces, nil
+}
+
+// addPins adds a hole contour to the slice for a pin pocket in every outer region that has room
+// for one and returns the 3D outline of each pocket. A pocket needs a wall at least one radius
+// thick around it in the slice plane and must stay inside the mesh for its full depth on both
+// sides of the plane
+func (s *Slice) addPins(bvh *BVH, radius, depth float32) [][]Vec3 {
+	normal := toVec3d(s.Plane.Normal).normalize()
+	pins := [][]Vec3{}
+	for _, polygon := range s.polygons() {
+		center, clearance := widestPoint(polygon)
+		if clearance < 2*float64(radius) {
+			continue
+		}
+		center3 := toVec3d(s.To3D(Vec2{float32(center.x), float32(center.y)}))
+
+		fits := true
+		for _, side := range []float64{-1, 1} {
+			for _, f := range []float64{0.5, 1} {
+				p := center3.add(normal.scale(side * f * float64(depth))).vec3()
+				if d, _ := bvh.SignedDistance(p); d > -radius {
+					fits = false
+				}
+			}
+		}
+		if !fits {
+			continue
+		}
+
+		// holes wind clockwise
+		hole := Contour{Hole: true}
+		for i := 0; i < pinSegments; i++ {
+			angle := -2 * math.Pi * float64(i) / pinSegments
+			p := Vec2{
+				float32(center.x + float64(radius)*math.Cos(angle)),
+				float32(center.y + float64(radius)*math.Sin(angle)),
+			}
+			hole.Points = append(hole.Points, p)
+			hole.points3 = append(hole.points3, s.To3D(p))
+		}
+		s.Contours = append(s.Contours, hole)
+		pins = append(pins, hole.points3)
+	}
+	return pins
+}
+
+// addHole adds a hole contour through points on the plane of the slice
+func (s *Slice) addHole(points []Vec3) {
+	origin := toVec3d(s.Plane.Origin)
+	u, v := toVec3d(s.U), toVec3d(s.V)
+	hole := Contour{Hole: true}
+	for _, p := range points {
+		d := toVec3d(p).sub(origin)
+		hole.Points = append(hole.Points, Vec2{float32(d.dot(u)), float32(d.dot(v))})
+		hole.points3 = append(hole.points3, p)
+	}
+	// holes wind clockwise
+	if hole.Area() > 0 {
+		hole.reverse()
+	}
+	s.Contours = append(s.Contours, hole)
+}
+
+// widestPoint searches a grid over an outer contour for the point inside the region that is
+// furthest from every edge of the region and returns it with that distance
+func widestPoint(polygon []*Contour) (vec2d, float64) {
+	outer := polygon[0]
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range outer.Points {
+		minX, maxX = math.Min(minX, float64(p.X)), math.Max(maxX, float64(p.X))
+		minY, maxY = math.Min(minY, float64(p.Y)), math.Max(maxY, float64(p.Y))
+	}
+
+	const steps = 32
+	best, bestDistance := vec2d{}, 0.0
+	for i := 0; i < steps; i++ {
+		for j := 0; j < steps; j++ {
+			p := vec2d{
+				minX + (maxX-minX)*(float64(i)+0.5)/steps,
+				minY + (maxY-minY)*(float64(j)+0.5)/steps,
+			}
+			p2 := Vec2{float32(p.x), float32(p.y)}
+			if !pointInPolygon(p2, outer.Points) {
+				continue
+			}
+			distance := math.Inf(1)
+			for k, c := range polygon {
+				if k > 0 && pointInPolygon(p2, c.Points) {
+					distance = 0
+					break
+				}
+				for e := range c.Points {
+					a, b := c.Points[e], c.Points[(e+1)%len(c.Points)]
+					distance = math.Min(distance, segmentDistance2D(p,
+						vec2d{float64(a.X), float64(a.Y)}, vec2d{float64(b.X), float64(b.Y)}))
+				}
+			}
+			if distance > bestDistance {
+				best, bestDistance = p, distance
+			}
+		}
+	}
+	return best, bestDistance
+}
+
+// segmentDistance2D returns the distance from p to the segment ab
+func segmentDistance2D(p, a, b vec2d) float64 {
+	ab := b.sub(a)
+	t := 0.0
+	if l := ab.dot(ab); l > 0 {
+		t = math.Max(0, math.Min(1, p.sub(a).dot(ab)/l))
+	}
+	return p.sub(a.add(ab.scale(t))).length()
+}
+
+// pocket builds the walls and floor of a pin pocket whose opening is the clockwise outline and
+// which reaches along depth. The triangles face into the pocket for the half below the cut plane
+// and are reversed for the half above it
+func pocket(outline []Vec3, depth Vec3, flip bool) []Triangle {
+	triangles := []Triangle{}
+	add := func(a, b, c Vec3) {
+		if flip {
+			b, c = c, b
+		}
+		t := Triangle{Vertices: [3]Vec3{a, b, c}}
+		t.Normal = t.ComputeNormal()
+		triangles = append(triangles, t)
+	}
+
+	n := len(outline)
+	floor := make([]Vec3, n)
+	for i, p := range outline {
+		floor[i] = p.Add(depth)
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		add(outline[j], outline[i], floor[i])
+		add(outline[j], floor[i], floor[j])
+	}
+	for i := 1; i < n-1; i++ {
+		add(floor[0], floor[i+1], floor[i])
+	}
+	return triangles
+}
@@ -0,0 +1,77 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSplitToFit(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{10, 4, 4})
+	volume := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{4, 4, 4}}
+
+	pieces, err := mesh.SplitToFit(volume, SplitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pieces) != 3 {
+		t.Fatalf("Expected 3 pieces, found: %d", len(pieces))
+	}
+	var total float32
+	for _, piece := range pieces {
+		box, _ := piece.BoundingBox()
+		size := box.Size()
+		if size.X > 4+1e-5 || size.Y > 4+1e-5 || size.Z > 4+1e-5 {
+			t.Errorf("Expected every piece to fit the build volume, found size: %v", size)
+		}
+		if !piece.Analyze().Watertight {
+			t.Errorf("Expected watertight pieces")
+		}
+		total += piece.Volume()
+	}
+	if !closeTo(total, 160, 1e-3) {
+		t.Errorf("Expected the pieces to add up to 160, found: %v", total)
+	}
+
+	if _, err := mesh.SplitToFit(AABB{}, SplitOptions{}); err != ErrInvalidBuildVolume {
+		t.Errorf("Expected ErrInvalidBuildVolume, found: %v", err)
+	}
+}
+
+func TestSplitToFitWithPins(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{10, 4, 4})
+	volume := AABB{Min: Vec3{0, 0, 0}, Max: Vec3{4, 4, 4}}
+
+	pieces, err := mesh.SplitToFit(volume, SplitOptions{PinRadius: 0.5, PinDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total float32
+	for _, piece := range pieces {
+		report := piece.Analyze()
+		if !report.Watertight || report.FlippedTriangles != 0 {
+			t.Errorf("Expected watertight, consistently oriented pieces, found: %+v", report)
+		}
+		total += piece.Volume()
+	}
+
+	// two cuts with a pocket on each side
+	pinArea := 0.5 * pinSegments * 0.25 * math.Sin(2*math.Pi/pinSegments)
+	expected := float32(160 - 4*pinArea)
+	if !closeTo(total, expected, 1e-3) {
+		t.Errorf("Expected the pieces to add up to %v, found: %v", expected, total)
+	}
+
+	// no room for a pin in a thin part
+	thin := makeBoxMesh(Vec3{0, 0, 0}, Vec3{10, 4, 0.5})
+	pieces, err = thin.SplitToFit(volume, SplitOptions{PinRadius: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total = 0
+	for _, piece := range pieces {
+		total += piece.Volume()
+	}
+	if !closeTo(total, 20, 1e-4) {
+		t.Errorf("Expected no pockets in a thin part, found a volume of: %v", total)
+	}
+}
@@ -0,0 +1,190 @@
+package meshful
+
+import (
+	"math"
+)
+
+// SubdivisionScheme selects how Subdivide places the new vertices
+type SubdivisionScheme int
+
+const (
+	// LoopSubdivision smooths the surface with Loop's scheme, which converges to a smooth limit
+	// surface
+	LoopSubdivision SubdivisionScheme = iota
+	// MidpointSubdivision splits every edge at its middle and moves nothing, so the shape stays
+	// exactly the same
+	MidpointSubdivision
+)
+
+// SubdivideOptions controls how Subdivide refines a mesh
+type SubdivideOptions struct {
+	Scheme SubdivisionScheme
+
+	// number of times every triangle is split into four
+	Iterations int
+
+	// edges where the faces meet at more than this angle in degrees are kept sharp, as are corners
+	// where a crease turns by more than it. 0 only keeps boundary edges sharp
+	CreaseAngle float32
+}
+
+// Subdivide returns a refined copy of the mesh where every iteration splits each triangle into
+// four by adding a vertex on every edge. With Loop subdivision the vertices are moved to smooth
+// the surface, while boundary and crease edges are smoothed only along their own curve so open
+// edges and sharp features keep their place. Triangles keep their colors
+func (mesh *Mesh) Subdivide(opts SubdivideOptions) *Mesh {
+	im := newIndexedMesh(mesh)
+	s := &subdivision{
+		positions: make([]vec3d, len(im.vertices)),
+		faces:     im.faces,
+		colors:    im.colors,
+		creases:   make(map[[2]int]bool),
+	}
+	for i, v := range im.vertices {
+		s.positions[i] = toVec3d(v)
+	}
+
+	// creases are found once on the original mesh and followed through the iterations
+	edges := im.edgeFaces()
+	cosine := math.Cos(float64(opts.CreaseAngle) * math.Pi / 180)
+	for _, key := range sortedEdgeKeys(edges) {
+		faces := edges[key]
+		if len(faces) != 2 {
+			s.creases[key] = true
+			continue
+		}
+		if opts.CreaseAngle > 0 {
+			a, b := s.faceNormal(faces[0]), s.faceNormal(faces[1])
+			if a.dot(b) < cosine {
+				s.creases[key] = true
+			}
+		}
+	}
+	s.corners = s.findCorners(opts.CreaseAngle)
+
+	for i := 0; i < opts.Iterations; i++ {
+		s.split(opts.Scheme == LoopSubdivision)
+	}
+
+	out := &indexedMesh{faces: s.faces, colors: s.colors, vertices: make([]Vec3, len(s.positions))}
+	for i, p := range s.positions {
+		out.vertices[i] = p.vec3()
+	}
+	return out.toMesh()
+}
+
+type subdivision struct {
+	positions []vec3d
+	faces     [][3]int
+	colors    []*Color
+	creases   map[[2]int]bool
+	// vertices that never move: where creases meet or end, or where a crease turns sharply
+	corners map[int]bool
+}
+
+func (s *subdivision) faceNormal(f int) vec3d {
+	face := s.faces[f]
+	a, b, c := s.positions[face[0]], s.positions[face[1]], s.positions[face[2]]
+	return b.sub(a).cross(c.sub(a)).normalize()
+}
+
+// creaseNeighbors returns the vertices joined to each vertex by crease edges
+func (s *subdivision) creaseNeighbors() map[int][]int {
+	neighbors := make(map[int][]int)
+	for key := range s.creases {
+		neighbors[key[0]] = append(neighbors[key[0]], key[1])
+		neighbors[key[1]] = append(neighbors[key[1]], key[0])
+	}
+	return neighbors
+}
+
+func (s *subdivision) findCorners(creaseAngle float32) map[int]bool {
+	corners := make(map[int]bool)
+	cosine := math.Cos(float64(creaseAngle) * math.Pi / 180)
+	for v, neighbors := range s.creaseNeighbors() {
+		if len(neighbors) != 2 {
+			corners[v] = true
+			continue
+		}
+		if creaseAngle > 0 {
+			in := s.positions[v].sub(s.positions[neighbors[0]]).normalize()
+			out := s.positions[neighbors[1]].sub(s.positions[v]).normalize()
+			if in.dot(out) < cosine {
+				corners[v] = true
+			}
+		}
+	}
+	return corners
+}
+
+// split divides every face into four. With smooth set the Loop rules place the vertices,
+// otherwise new vertices go at edge midpoints
+func (s *subdivision) split(smooth bool) {
+	// opposite corners of the faces around each edge
+	opposite := make(map[[2]int][]int)
+	for _, face := range s.faces {
+		for k := 0; k < 3; k++ {
+			key := edgeKey(face[k], face[(k+1)%3])
+			opposite[key] = append(opposite[key], face[(k+2)%3])
+		}
+	}
+
+	positions := make([]vec3d, len(s.positions), len(s.positions)+len(opposite))
+	midpoints := make(map[[2]int]int, len(opposite))
+	for _, key := range sortedEdgeKeys(opposite) {
+		a, b := s.positions[key[0]], s.positions[key[1]]
+		p := a.add(b).scale(0.5)
+		if corners := opposite[key]; smooth && !s.creases[key] && len(corners) == 2 {
+			c, d := s.positions[corners[0]], s.positions[corners[1]]
+			p = a.add(b).scale(3.0 / 8).add(c.add(d).scale(1.0 / 8))
+		}
+		midpoints[key] = len(positions)
+		positions = append(positions, p)
+	}
+
+	copy(positions, s.positions)
+	if smooth {
+		ring := make([][]int, len(s.positions))
+		for key := range opposite {
+			ring[key[0]] = append(ring[key[0]], key[1])
+			ring[key[1]] = append(ring[key[1]], key[0])
+		}
+		creaseNeighbors := s.creaseNeighbors()
+		for v, p := range s.positions {
+			switch {
+			case s.corners[v] || len(ring[v]) == 0:
+			case len(creaseNeighbors[v]) == 2:
+				c := creaseNeighbors[v]
+				positions[v] = p.scale(3.0 / 4).add(s.positions[c[0]].add(s.positions[c[1]]).scale(1.0 / 8))
+			default:
+				n := float64(len(ring[v]))
+				x := 3.0/8 + math.Cos(2*math.Pi/n)/4
+				beta := (5.0/8 - x*x) / n
+				sum := vec3d{}
+				for _, w := range ring[v] {
+					sum = sum.add(s.positions[w])
+				}
+				positions[v] = p.scale(1 - n*beta).add(sum.scale(beta))
+			}
+		}
+	}
+
+	faces := make([][3]int, 0, 4*len(s.faces))
+	colors := make([]*Color, 0, 4*len(s.faces))
+	for f, face := range s.faces {
+		a, b, c := face[0], face[1], face[2]
+		ab, bc, ca := midpoints[edgeKey(a, b)], midpoints[edgeKey(b, c)], midpoints[edgeKey(c, a)]
+		faces = append(faces, [3]int{a, ab, ca}, [3]int{ab, b, bc}, [3]int{ca, bc, c}, [3]int{ab, bc, ca})
+		colors = append(colors, s.colors[f], s.colors[f], s.colors[f], s.colors[f])
+	}
+
+	// both halves of a crease edge are creases
+	creases := make(map[[2]int]bool, 2*len(s.creases))
+	for key := range s.creases {
+		m := midpoints[key]
+		creases[edgeKey(key[0], m)] = true
+		creases[edgeKey(m, key[1])] = true
+	}
+
+	s.positions, s.faces, s.colors, s.creases = positions, faces, colors, creases
+}
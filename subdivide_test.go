@@ -0,0 +1,77 @@
+package meshful
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMidpointSubdivision(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{0, 0, 0}, Vec3{2, 3, 4})
+	refined := mesh.Subdivide(SubdivideOptions{Scheme: MidpointSubdivision, Iterations: 2})
+	if len(refined.Triangles) != 16*len(mesh.Triangles) {
+		t.Fatalf("Expected %d triangles, found: %d", 16*len(mesh.Triangles), len(refined.Triangles))
+	}
+	if !closeTo(refined.Volume(), 24, 1e-4) || !closeTo(refined.SurfaceArea(), mesh.SurfaceArea(), 1e-4) {
+		t.Errorf("Expected the shape to be unchanged, found volume %v and area %v", refined.Volume(), refined.SurfaceArea())
+	}
+	if !refined.Analyze().Watertight {
+		t.Errorf("Expected a closed result")
+	}
+}
+
+func TestLoopSubdivision(t *testing.T) {
+	mesh := makeBoxMesh(Vec3{-1, -1, -1}, Vec3{1, 1, 1})
+	smooth := mesh.Subdivide(SubdivideOptions{Iterations: 3})
+	report := smooth.Analyze()
+	if len(smooth.Triangles) != 64*12 || !report.Watertight || report.FlippedTriangles != 0 {
+		t.Errorf("Expected 768 closed, consistently oriented triangles, found: %+v", report)
+	}
+	// smoothing pulls the corners in
+	box, _ := smooth.BoundingBox()
+	if v := smooth.Volume(); v >= 8 || v < 2 || box.Max.X >= 1 {
+		t.Errorf("Expected the box to be rounded off, found volume %v and bounds %v", v, box)
+	}
+
+	// with creases at the box edges nothing moves
+	sharp := mesh.Subdivide(SubdivideOptions{Iterations: 2, CreaseAngle: 30})
+	if !closeTo(sharp.Volume(), 8, 1e-4) {
+		t.Errorf("Expected creases to keep the box, found volume: %v", sharp.Volume())
+	}
+
+	// a sphere stays a sphere
+	sphere := makeSphereMesh(Vec3{0, 0, 0}, 1, 8, 16).Subdivide(SubdivideOptions{Iterations: 2})
+	for _, tri := range sphere.Triangles {
+		for _, v := range tri.Vertices {
+			if r := toVec3d(v).length(); math.Abs(r-1) > 0.1 {
+				t.Errorf("Expected vertices near the sphere, found radius: %v", r)
+			}
+		}
+	}
+}
+
+func TestLoopSubdivisionBoundary(t *testing.T) {
+	mesh := makeGridMesh(4)
+	// lift the middle vertex so smoothing has something to do
+	for i := range mesh.Triangles {
+		for j, v := range mesh.Triangles[i].Vertices {
+			if v.X == 0.5 && v.Y == 0.5 {
+				mesh.Triangles[i].Vertices[j].Z = 0.1
+			}
+		}
+	}
+	smooth := mesh.Subdivide(SubdivideOptions{Iterations: 2, CreaseAngle: 60})
+	if len(smooth.Holes()) != 1 {
+		t.Errorf("Expected the boundary to stay a single loop")
+	}
+	box, _ := smooth.BoundingBox()
+	if box.Min.X != 0 || box.Min.Y != 0 || box.Max.X != 1 || box.Max.Y != 1 || box.Max.Z >= 0.1 {
+		t.Errorf("Expected the boundary corners to stay and the peak to be smoothed, found: %v", box)
+	}
+	for _, tri := range smooth.Triangles {
+		for _, v := range tri.Vertices {
+			if (v.X == 0 || v.X == 1 || v.Y == 0 || v.Y == 1) && v.Z != 0 {
+				t.Errorf("Expected boundary vertices to stay on the boundary, found: %v", v)
+			}
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package meshful
+
+import (
+	"math"
+	"sort"
+)
+
+// indexedMesh is a shared-vertex view of a mesh. Vertices at exactly the same position are
+// merged so that triangles can be related to each other through their vertices and edges
+type indexedMesh struct {
+	vertices []Vec3
+	// each face holds the indices of its 3 vertices and lines up with mesh.Triangles until the
+	// faces are edited
+	faces [][3]int
+	// color of each face
+	colors []*Color
+}
+
+func newIndexedMesh(mesh *Mesh) *indexedMesh {
+	lookup := make(map[Vec3]int)
+	im := &indexedMesh{
+		faces:  make([][3]int, len(mesh.Triangles)),
+		colors: make([]*Color, len(mesh.Triangles)),
+	}
+	for i, triangle := range mesh.Triangles {
+		for j, v := range triangle.Vertices {
+			index, exists := lookup[v]
+			if !exists {
+				index = len(im.vertices)
+				lookup[v] = index
+				im.vertices = append(im.vertices, v)
+			}
+			im.faces[i][j] = index
+		}
+		im.colors[i] = triangle.Color
+	}
+	return im
+}
+
+// newWeldedIndexedMesh is like newIndexedMesh but also merges vertices that are within
+// tolerance of each other. Each vertex is merged into the first vertex found within range
+func newWeldedIndexedMesh(mesh *Mesh, tolerance float32) *indexedMesh {
+	if tolerance <= 0 {
+		return newIndexedMesh(mesh)
+	}
+
+	// hash the vertices into a grid with cells the size of the tolerance so only the neighboring
+	// cells have to be searched
+	cellOf := func(v Vec3) [3]int64 {
+		return [3]int64{
+			int64(math.Floor(float64(v.X / tolerance))),
+			int64(math.Floor(float64(v.Y / tolerance))),
+			int64(math.Floor(float64(v.Z / tolerance))),
+		}
+	}
+	grid := make(map[[3]int64][]int)
+	exact := make(map[Vec3]int)
+
+	im := &indexedMesh{
+		faces:  make([][3]int, len(mesh.Triangles)),
+		colors: make([]*Color, len(mesh.Triangles)),
+	}
+	limit := float64(tolerance) * float64(tolerance)
+	for i, triangle := range mesh.Triangles {
+		for j, v := range triangle.Vertices {
+			index, exists := exact[v]
+			if !exists {
+				index = -1
+				cell := cellOf(v)
+				for dx := int64(-1); dx <= 1 && index < 0; dx++ {
+					for dy := int64(-1); dy <= 1 && index < 0; dy++ {
+						for dz := int64(-1); dz <= 1 && index < 0; dz++ {
+							for _, candidate := range grid[[3]int64{cell[0] + dx, cell[1] + dy, cell[2] + dz}] {
+								d := toVec3d(im.vertices[candidate]).sub(toVec3d(v))
+								if d.dot(d) <= limit {
+									index = candidate
+									break
+								}
+							}
+						}
+					}
+				}
+				if index < 0 {
+					index = len(im.vertices)
+					im.vertices = append(im.vertices, v)
+					grid[cell] = append(grid[cell], index)
+				}
+				exact[v] = index
+			}
+			im.faces[i][j] = index
+		}
+		im.colors[i] = triangle.Color
+	}
+	return im
+}
+
+// toMesh converts the indexed mesh back into a triangle mesh with normals computed from the
+// winding of each face
+func (im *indexedMesh) toMesh() *Mesh {
+	mesh := &Mesh{Triangles: make([]Triangle, len(im.faces))}
+	for i, face := range im.faces {
+		t := &mesh.Triangles[i]
+		t.Vertices = [3]Vec3{im.vertices[face[0]], im.vertices[face[1]], im.vertices[face[2]]}
+		t.Normal = t.ComputeNormal()
+		if i < len(im.colors) {
+			t.Color = im.colors[i]
+		}
+	}
+	return mesh
+}
+
+// keepFaces removes every face that is not marked to be kept
+func (im *indexedMesh) keepFaces(keep []bool) {
+	faces := im.faces[:0]
+	colors := im.colors[:0]
+	for i, face := range im.faces {
+		if keep[i] {
+			faces = append(faces, face)
+			colors = append(colors, im.colors[i])
+		}
+	}
+	im.faces = faces
+	im.colors = colors
+}
+
+// edgeKey returns the key for the undirected edge between two vertices
+func edgeKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// edgeFaces maps every undirected edge to the faces that use it
+func (im *indexedMesh) edgeFaces() map[[2]int][]int {
+	edges := make(map[[2]int][]int)
+	for i, face := range im.faces {
+		for j := 0; j < 3; j++ {
+			key := edgeKey(face[j], face[(j+1)%3])
+			edges[key] = append(edges[key], i)
+		}
+	}
+	return edges
+}
+
+// hasDirectedEdge reports whether the face traverses the edge from a to b
+func hasDirectedEdge(face [3]int, a, b int) bool {
+	for j := 0; j < 3; j++ {
+		if face[j] == a && face[(j+1)%3] == b {
+			return true
+		}
+	}
+	return false
+}
+
+// components groups the faces into sets that are connected through shared edges
+func (im *indexedMesh) components(edges map[[2]int][]int) [][]int {
+	visited := make([]bool, len(im.faces))
+	components := [][]int{}
+	for start := range im.faces {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		component := []int{start}
+		for next := 0; next < len(component); next++ {
+			face := im.faces[component[next]]
+			for j := 0; j < 3; j++ {
+				for _, neighbor := range edges[edgeKey(face[j], face[(j+1)%3])] {
+					if !visited[neighbor] {
+						visited[neighbor] = true
+						component = append(component, neighbor)
+					}
+				}
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// vertexFaces maps every vertex to the faces that use it
+func (im *indexedMesh) vertexFaces() [][]int {
+	faces := make([][]int, len(im.vertices))
+	for i, face := range im.faces {
+		for _, v := range face {
+			faces[v] = append(faces[v], i)
+		}
+	}
+	return faces
+}
+
+// boundaryLoops returns the closed loops of edges that are only used by one face. Each loop runs
+// in the opposite direction of its faces, which is the winding a triangle filling the hole needs
+func (im *indexedMesh) boundaryLoops(edges map[[2]int][]int) [][]int {
+	// next maps a vertex to the following vertices along the boundary
+	next := make(map[int][]int)
+	starts := []int{}
+	for _, key := range sortedEdgeKeys(edges) {
+		faces := edges[key]
+		if len(faces) != 1 {
+			continue
+		}
+		a, b := key[0], key[1]
+		if !hasDirectedEdge(im.faces[faces[0]], a, b) {
+			a, b = b, a
+		}
+		// the face runs from a to b so the hole runs from b to a
+		next[b] = append(next[b], a)
+		starts = append(starts, b)
+	}
+
+	loops := [][]int{}
+	for _, start := range starts {
+		if len(next[start]) == 0 {
+			continue
+		}
+		loop := []int{start}
+		current := start
+		for {
+			candidates := next[current]
+			if len(candidates) == 0 {
+				// the boundary does not close up, which can happen around non-manifold vertices
+				loop = nil
+				break
+			}
+			following := candidates[len(candidates)-1]
+			next[current] = candidates[:len(candidates)-1]
+			if following == start {
+				break
+			}
+			loop = append(loop, following)
+			current = following
+		}
+		if len(loop) >= 3 {
+			loops = append(loops, loop)
+		}
+	}
+	return loops
+}
+
+// sortedEdgeKeys returns the keys of the edge map in a deterministic order
+func sortedEdgeKeys(edges map[[2]int][]int) [][2]int {
+	keys := make([][2]int, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// vertexComponents groups the faces into sets that are connected through shared vertices
+func (im *indexedMesh) vertexComponents() [][]int {
+	parent := make([]int, len(im.vertices))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+	for _, face := range im.faces {
+		for j := 1; j < 3; j++ {
+			parent[find(face[j])] = find(face[0])
+		}
+	}
+
+	index := make(map[int]int)
+	components := [][]int{}
+	for i, face := range im.faces {
+		root := find(face[0])
+		c, exists := index[root]
+		if !exists {
+			c = len(components)
+			index[root] = c
+			components = append(components, nil)
+		}
+		components[c] = append(components[c], i)
+	}
+	return components
+}
@@ -0,0 +1,143 @@
+package meshful
+
+import (
+	"math"
+)
+
+// polygonArea2D returns the signed area of a polygon, positive when it is counter-clockwise
+func polygonArea2D(poly []vec2d) float64 {
+	var area float64
+	for i := range poly {
+		a := poly[i]
+		b := poly[(i+1)%len(poly)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
+
+// earClip triangulates a simple counter-clockwise polygon by repeatedly cutting off convex
+// corners that contain no other polygon vertex. The triangles index into poly and are counter-
+// clockwise. The second return value is false if the polygon could not be fully triangulated,
+// which happens when it intersects itself
+func earClip(poly []vec2d) ([][3]int, bool) {
+	remaining := make([]int, len(poly))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	triangles := [][3]int{}
+
+	for len(remaining) > 3 {
+		found := false
+		n := len(remaining)
+		for i := 0; i < n; i++ {
+			prev := remaining[(i+n-1)%n]
+			curr := remaining[i]
+			next := remaining[(i+1)%n]
+			if !isEar(poly, remaining, prev, curr, next) {
+				continue
+			}
+			triangles = append(triangles, [3]int{prev, curr, next})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			found = true
+			break
+		}
+		if !found {
+			// no ear was found, so cut off the least concave corner to make progress
+			best, bestCross := 0, math.Inf(-1)
+			for i := 0; i < n; i++ {
+				c := cross2D(poly[remaining[(i+n-1)%n]], poly[remaining[i]], poly[remaining[(i+1)%n]])
+				if c > bestCross {
+					best, bestCross = i, c
+				}
+			}
+			triangles = append(triangles, [3]int{remaining[(best+n-1)%n], remaining[best], remaining[(best+1)%n]})
+			remaining = append(remaining[:best], remaining[best+1:]...)
+			if bestCross <= 0 {
+				triangles = append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+				return triangles, false
+			}
+		}
+	}
+	triangles = append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+	return triangles, true
+}
+
+// isEar reports whether the corner at curr is convex and no other vertex lies inside the
+// triangle it forms with its neighbors
+func isEar(poly []vec2d, remaining []int, prev, curr, next int) bool {
+	a, b, c := poly[prev], poly[curr], poly[next]
+	if cross2D(a, b, c) <= 0 {
+		return false
+	}
+	for _, i := range remaining {
+		if i == prev || i == curr || i == next {
+			continue
+		}
+		p := poly[i]
+		// vertices at the same position as a corner come from bridges and do not block the ear
+		if p == a || p == b || p == c {
+			continue
+		}
+		if cross2D(a, b, p) >= 0 && cross2D(b, c, p) >= 0 && cross2D(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// minimumAreaTriangulation triangulates a closed 3D polygon so that the total area of the
+// triangles is as small as possible using dynamic programming. It runs in cubic time so it is only
+// suitable for small polygons. The triangles index into loop and follow its winding
+func minimumAreaTriangulation(loop []vec3d) [][3]int {
+	n := len(loop)
+	// cost[i][j] is the smallest area of a triangulation of the polygon i..j
+	cost := make([][]float64, n)
+	split := make([][]int, n)
+	for i := range cost {
+		cost[i] = make([]float64, n)
+		split[i] = make([]int, n)
+	}
+
+	for length := 2; length < n; length++ {
+		for i := 0; i+length < n; i++ {
+			j := i + length
+			cost[i][j] = math.Inf(1)
+			for k := i + 1; k < j; k++ {
+				area := loop[k].sub(loop[i]).cross(loop[j].sub(loop[i])).length() / 2
+				c := cost[i][k] + cost[k][j] + area
+				if c < cost[i][j] {
+					cost[i][j] = c
+					split[i][j] = k
+				}
+			}
+		}
+	}
+
+	triangles := [][3]int{}
+	var collect func(i, j int)
+	collect = func(i, j int) {
+		if j-i < 2 {
+			return
+		}
+		k := split[i][j]
+		triangles = append(triangles, [3]int{i, k, j})
+		collect(i, k)
+		collect(k, j)
+	}
+	collect(0, n-1)
+	return triangles
+}
+
+// planeBasis returns two unit vectors that span the plane perpendicular to normal and form a
+// right-handed frame with it. For horizontal planes they are the x and y axes so slice coordinates
+// match the mesh coordinates
+func planeBasis(normal vec3d) (vec3d, vec3d) {
+	normal = normal.normalize()
+	reference := vec3d{1, 0, 0}
+	if math.Abs(normal.x) > 0.9 {
+		reference = vec3d{0, 1, 0}
+	}
+	u := reference.sub(normal.scale(reference.dot(normal))).normalize()
+	v := normal.cross(u)
+	return u, v
+}
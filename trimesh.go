@@ -0,0 +1,282 @@
+package meshful
+
+// trimesh is an indexed mesh that supports local edits like edge collapses. Faces are removed by
+// marking them dead, and every vertex keeps a list of the faces around it that is cleaned up
+// lazily
+type trimesh struct {
+	positions []vec3d
+	faces     [][3]int
+	colors    []*Color
+	alive     []bool
+	// faces around each vertex, which may include faces that are no longer alive
+	vertexFaces [][]int
+}
+
+func newTrimesh(im *indexedMesh) *trimesh {
+	tm := &trimesh{
+		positions:   make([]vec3d, len(im.vertices)),
+		faces:       append([][3]int{}, im.faces...),
+		colors:      append([]*Color{}, im.colors...),
+		alive:       make([]bool, len(im.faces)),
+		vertexFaces: make([][]int, len(im.vertices)),
+	}
+	for i, v := range im.vertices {
+		tm.positions[i] = toVec3d(v)
+	}
+	for f, face := range im.faces {
+		tm.alive[f] = true
+		for _, v := range face {
+			tm.vertexFaces[v] = append(tm.vertexFaces[v], f)
+		}
+	}
+	return tm
+}
+
+// facesOf returns the living faces around a vertex
+func (tm *trimesh) facesOf(v int) []int {
+	faces := tm.vertexFaces[v][:0]
+	for _, f := range tm.vertexFaces[v] {
+		if tm.alive[f] && (tm.faces[f][0] == v || tm.faces[f][1] == v || tm.faces[f][2] == v) {
+			faces = append(faces, f)
+		}
+	}
+	tm.vertexFaces[v] = faces
+	return faces
+}
+
+// neighbors returns the vertices that share an edge with v
+func (tm *trimesh) neighbors(v int) []int {
+	seen := make(map[int]bool)
+	neighbors := []int{}
+	for _, f := range tm.facesOf(v) {
+		for _, w := range tm.faces[f] {
+			if w != v && !seen[w] {
+				seen[w] = true
+				neighbors = append(neighbors, w)
+			}
+		}
+	}
+	return neighbors
+}
+
+// edgeFaces returns the living faces that use the edge between u and v
+func (tm *trimesh) edgeFaces(u, v int) []int {
+	faces := []int{}
+	for _, f := range tm.facesOf(u) {
+		face := tm.faces[f]
+		if face[0] == v || face[1] == v || face[2] == v {
+			faces = append(faces, f)
+		}
+	}
+	return faces
+}
+
+// faceCount returns the number of living faces
+func (tm *trimesh) faceCount() int {
+	count := 0
+	for _, a := range tm.alive {
+		if a {
+			count++
+		}
+	}
+	return count
+}
+
+// faceNormal returns the unnormalized normal of a face, as if vertex moved were at position p
+func (tm *trimesh) faceNormal(f int, moved map[int]bool, p vec3d) vec3d {
+	var corners [3]vec3d
+	for k, v := range tm.faces[f] {
+		corners[k] = tm.positions[v]
+		if moved[v] {
+			corners[k] = p
+		}
+	}
+	return corners[1].sub(corners[0]).cross(corners[2].sub(corners[0]))
+}
+
+// canCollapse checks the link condition: the only vertices connected to both ends of the edge
+// must be the corners opposite it. Collapsing other edges would pinch the surface
+func (tm *trimesh) canCollapse(u, v int) bool {
+	shared := tm.edgeFaces(u, v)
+	if len(shared) == 0 || len(shared) > 2 {
+		return false
+	}
+	opposite := make(map[int]bool)
+	for _, f := range shared {
+		for _, w := range tm.faces[f] {
+			if w != u && w != v {
+				opposite[w] = true
+			}
+		}
+	}
+	common := 0
+	around := make(map[int]bool)
+	for _, w := range tm.neighbors(u) {
+		around[w] = true
+	}
+	for _, w := range tm.neighbors(v) {
+		if around[w] {
+			if !opposite[w] {
+				return false
+			}
+			common++
+		}
+	}
+	return common == len(shared)
+}
+
+// flipsFaces reports whether moving both ends of the edge to p would turn a remaining face over or
+// make it degenerate
+func (tm *trimesh) flipsFaces(u, v int, p vec3d) bool {
+	moved := map[int]bool{u: true, v: true}
+	for _, w := range []int{u, v} {
+		for _, f := range tm.facesOf(w) {
+			face := tm.faces[f]
+			if (face[0] == u || face[1] == u || face[2] == u) && (face[0] == v || face[1] == v || face[2] == v) {
+				continue
+			}
+			if tm.folds(f, moved, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// movingFolds reports whether moving v to p would turn any face around it over
+func (tm *trimesh) movingFolds(v int, p vec3d) bool {
+	moved := map[int]bool{v: true}
+	for _, f := range tm.facesOf(v) {
+		if tm.folds(f, moved, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// folds reports whether moving the vertices in moved to p would turn face f over or make it
+// degenerate
+func (tm *trimesh) folds(f int, moved map[int]bool, p vec3d) bool {
+	before := tm.faceNormal(f, nil, vec3d{})
+	after := tm.faceNormal(f, moved, p)
+	return after.dot(before) <= 0 || after.length() == 0
+}
+
+// collapse merges v into u and moves u to p. The faces on the edge are removed
+func (tm *trimesh) collapse(u, v int, p vec3d) {
+	for _, f := range tm.facesOf(v) {
+		face := &tm.faces[f]
+		if face[0] == u || face[1] == u || face[2] == u {
+			tm.alive[f] = false
+			continue
+		}
+		for k := range face {
+			if face[k] == v {
+				face[k] = u
+			}
+		}
+		tm.vertexFaces[u] = append(tm.vertexFaces[u], f)
+	}
+	tm.vertexFaces[v] = nil
+	tm.positions[u] = p
+}
+
+// addFace adds a living face
+func (tm *trimesh) addFace(face [3]int, color *Color) int {
+	f := len(tm.faces)
+	tm.faces = append(tm.faces, face)
+	tm.colors = append(tm.colors, color)
+	tm.alive = append(tm.alive, true)
+	for _, v := range face {
+		tm.vertexFaces[v] = append(tm.vertexFaces[v], f)
+	}
+	return f
+}
+
+// splitEdge adds a vertex at p on the edge between u and v and splits the faces on both sides of
+// the edge in two. The index of the new vertex is returned
+func (tm *trimesh) splitEdge(u, v int, p vec3d) int {
+	m := len(tm.positions)
+	tm.positions = append(tm.positions, p)
+	tm.vertexFaces = append(tm.vertexFaces, nil)
+	for _, f := range tm.edgeFaces(u, v) {
+		face := tm.faces[f]
+		k := 0
+		for face[k] != u && face[k] != v || face[(k+1)%3] != u && face[(k+1)%3] != v {
+			k++
+		}
+		a, b, c := face[k], face[(k+1)%3], face[(k+2)%3]
+		tm.faces[f] = [3]int{a, m, c}
+		tm.vertexFaces[m] = append(tm.vertexFaces[m], f)
+		tm.addFace([3]int{m, b, c}, tm.colors[f])
+	}
+	return m
+}
+
+// flipEdge replaces the edge between u and v with the edge between the two corners opposite it.
+// The edge must have exactly two faces
+func (tm *trimesh) flipEdge(u, v int) {
+	faces, flipped := tm.flippedFaces(u, v)
+	tm.faces[faces[0]], tm.faces[faces[1]] = flipped[0], flipped[1]
+	// the corner opposite the edge in each face joins the other face
+	a, b := flipped[0][2], flipped[0][1]
+	tm.vertexFaces[b] = append(tm.vertexFaces[b], faces[0])
+	tm.vertexFaces[a] = append(tm.vertexFaces[a], faces[1])
+}
+
+// flippedFaces returns the two faces on the edge between u and v and the faces flipEdge replaces
+// them with, oriented the same way
+func (tm *trimesh) flippedFaces(u, v int) ([2]int, [2][3]int) {
+	faces := [2]int{}
+	copy(faces[:], tm.edgeFaces(u, v))
+	// make the first face run from u to v
+	if !hasDirectedEdge(tm.faces[faces[0]], u, v) {
+		faces[0], faces[1] = faces[1], faces[0]
+	}
+	a := tm.oppositeCorner(faces[0], u, v)
+	b := tm.oppositeCorner(faces[1], u, v)
+	return faces, [2][3]int{{u, b, a}, {b, v, a}}
+}
+
+// oppositeCorner returns the corner of the face that is not on the edge between u and v
+func (tm *trimesh) oppositeCorner(f, u, v int) int {
+	for _, w := range tm.faces[f] {
+		if w != u && w != v {
+			return w
+		}
+	}
+	return -1
+}
+
+// vertexNormal returns the area weighted normal of the faces around a vertex
+func (tm *trimesh) vertexNormal(v int) vec3d {
+	normal := vec3d{}
+	for _, f := range tm.facesOf(v) {
+		normal = normal.add(tm.faceNormal(f, nil, vec3d{}))
+	}
+	return normal.normalize()
+}
+
+// toIndexed returns the living faces as an indexed mesh without unused vertices
+func (tm *trimesh) toIndexed() *indexedMesh {
+	im := &indexedMesh{}
+	remap := make(map[int]int)
+	for f, face := range tm.faces {
+		if !tm.alive[f] {
+			continue
+		}
+		var mapped [3]int
+		for k, v := range face {
+			i, ok := remap[v]
+			if !ok {
+				i = len(im.vertices)
+				remap[v] = i
+				im.vertices = append(im.vertices, tm.positions[v].vec3())
+			}
+			mapped[k] = i
+		}
+		im.faces = append(im.faces, mapped)
+		im.colors = append(im.colors, tm.colors[f])
+	}
+	return im
+}
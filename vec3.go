@@ -29,3 +29,53 @@ func (vec Vec3) Diff(other Vec3) Vec3 {
 		vec.Z - other.Z,
 	}
 }
+
+// add returns the sum of two vectors
+func (vec Vec3) Add(other Vec3) Vec3 {
+	return Vec3{
+		vec.X + other.X,
+		vec.Y + other.Y,
+		vec.Z + other.Z,
+	}
+}
+
+// scale returns the vector multiplied by a scalar
+func (vec Vec3) Scale(s float32) Vec3 {
+	return Vec3{
+		vec.X * s,
+		vec.Y * s,
+		vec.Z * s,
+	}
+}
+
+// min returns the component-wise minimum of two vectors
+func (vec Vec3) Min(other Vec3) Vec3 {
+	return Vec3{
+		min32(vec.X, other.X),
+		min32(vec.Y, other.Y),
+		min32(vec.Z, other.Z),
+	}
+}
+
+// max returns the component-wise maximum of two vectors
+func (vec Vec3) Max(other Vec3) Vec3 {
+	return Vec3{
+		max32(vec.X, other.X),
+		max32(vec.Y, other.Y),
+		max32(vec.Z, other.Z),
+	}
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,57 @@
+package meshful
+
+import (
+	"math"
+)
+
+// vec3d is a double precision vector used internally by algorithms where accumulating
+// float32 rounding error would give wrong answers
+type vec3d struct {
+	x, y, z float64
+}
+
+func toVec3d(v Vec3) vec3d {
+	return vec3d{float64(v.X), float64(v.Y), float64(v.Z)}
+}
+
+// vec3 converts the vector back to single precision
+func (v vec3d) vec3() Vec3 {
+	return Vec3{float32(v.x), float32(v.y), float32(v.z)}
+}
+
+func (v vec3d) add(o vec3d) vec3d {
+	return vec3d{v.x + o.x, v.y + o.y, v.z + o.z}
+}
+
+func (v vec3d) sub(o vec3d) vec3d {
+	return vec3d{v.x - o.x, v.y - o.y, v.z - o.z}
+}
+
+func (v vec3d) scale(s float64) vec3d {
+	return vec3d{v.x * s, v.y * s, v.z * s}
+}
+
+func (v vec3d) dot(o vec3d) float64 {
+	return v.x*o.x + v.y*o.y + v.z*o.z
+}
+
+func (v vec3d) cross(o vec3d) vec3d {
+	return vec3d{
+		v.y*o.z - v.z*o.y,
+		v.z*o.x - v.x*o.z,
+		v.x*o.y - v.y*o.x,
+	}
+}
+
+func (v vec3d) length() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// normalize returns the unit vector in the same direction, or the zero vector if v has no length
+func (v vec3d) normalize() vec3d {
+	l := v.length()
+	if l == 0 {
+		return vec3d{}
+	}
+	return v.scale(1 / l)
+}